@@ -0,0 +1,557 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/SENERGY-Platform/import-deploy/lib/client/v2"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+func init() {
+	commands["list"] = command{usage: "list [filter flags] [-limit n] [-sort field] [-desc]", description: "list instances", run: list}
+	commands["get"] = command{usage: "get <id>", description: "show an instance", run: get}
+	commands["create"] = command{usage: "create -f <file> [-idempotency-key key]", description: "create an instance from a json or yaml file", run: create}
+	commands["update"] = command{usage: "update <id> (-f <file> | -set config=value... [-name name])", description: "replace an instance or change single configs", run: update}
+	commands["delete"] = command{usage: "delete <id>...", description: "delete instances", run: remove}
+	commands["count"] = command{usage: "count [filter flags]", description: "count instances", run: count}
+	commands["status"] = command{usage: "status [id]", description: "show the readiness of the server or the status and data statistics of an instance", run: status}
+	commands["logs"] = command{usage: "logs <id> [-tail n]", description: "show the latest log lines of an instance container", run: logs}
+	commands["apply"] = command{usage: "apply -f <manifest> [-dry-run] [-prune]", description: "create, update and delete instances to match a manifest", run: apply}
+	commands["export"] = command{usage: "export [filter flags] [-id id...] [-include-secrets] [-bundle]", description: "export the instances managed by apply as manifest, or all instances as bundle", run: export}
+	commands["import"] = command{usage: "import -f <bundle> [-dry-run] [-on-conflict fail|skip|update|create] [-import-type-map old:new...]", description: "create or update instances from an exported bundle", run: importBundle}
+}
+
+func newFlags(env env, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "usage: importctl "+commands[name].usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs allows flags after positional arguments
+func parseArgs(flags *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		err = flags.Parse(args)
+		if err != nil {
+			return positional, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+func expectArgs(flags *flag.FlagSet, args []string, n int) error {
+	if len(args) != n {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	return nil
+}
+
+// filterFlags registers the instance filter flags and returns a function to read them after parsing
+func filterFlags(flags *flag.FlagSet) func() (model.InstanceFilter, error) {
+	search := flags.String("search", "", "substring of the name")
+	importTypeId := flags.String("import-type", "", "import type id")
+	generated := flags.String("generated", "", "true or false")
+	labels := listFlag{}
+	flags.Var(&labels, "label", "key:value, may be repeated")
+	return func() (filter model.InstanceFilter, err error) {
+		filter = model.InstanceFilter{Search: *search, ImportTypeId: *importTypeId}
+		if *generated != "" {
+			value, err := strconv.ParseBool(*generated)
+			if err != nil {
+				return filter, errors.New("invalid -generated: " + err.Error())
+			}
+			filter.Generated = &value
+		}
+		for _, label := range labels {
+			key, value, ok := strings.Cut(label, ":")
+			if !ok {
+				return filter, errors.New("-label has to be in the form key:value")
+			}
+			if filter.Labels == nil {
+				filter.Labels = map[string]string{}
+			}
+			filter.Labels[key] = value
+		}
+		return filter, nil
+	}
+}
+
+func list(env env, args []string) error {
+	flags := newFlags(env, "list")
+	getFilter := filterFlags(flags)
+	limit := flags.Int64("limit", 0, "maximum number of instances, 0 for all")
+	sort := flags.String("sort", "name", "name, import_type_id, created_at or updated_at")
+	desc := flags.Bool("desc", false, "sort descending")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	filter, err := getFilter()
+	if err != nil {
+		return err
+	}
+	options := client.ListOptions{Limit: 100, Sort: *sort, Desc: *desc, Filter: filter}
+	instances := []model.Instance{}
+	for {
+		if *limit > 0 {
+			options.Limit = min(100, *limit-int64(len(instances)))
+		}
+		page, err := env.client.ListInstances(env.ctx, env.token, options)
+		if err != nil {
+			return err
+		}
+		instances = append(instances, page.Items...)
+		if page.NextCursor == "" || (*limit > 0 && int64(len(instances)) >= *limit) {
+			break
+		}
+		options.Cursor = page.NextCursor
+	}
+	return env.out.print(instances, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tIMPORT TYPE\tSTALE\tUPDATED\tLABELS")
+		for _, instance := range instances {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", instance.Id, instance.Name, instance.ImportTypeId, instance.Stale, instance.UpdatedAt.Format(time.RFC3339), formatLabels(instance.Labels))
+		}
+	})
+}
+
+func get(env env, args []string) error {
+	flags := newFlags(env, "get")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 1); err != nil {
+		return err
+	}
+	instance, err := env.client.ReadInstance(env.ctx, env.token, args[0])
+	if err != nil {
+		return err
+	}
+	return printInstance(env, instance)
+}
+
+func printInstance(env env, instance model.Instance) error {
+	return env.out.print(instance, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%v\n", instance.Id)
+		fmt.Fprintf(w, "NAME:\t%v\n", instance.Name)
+		fmt.Fprintf(w, "IMPORT TYPE:\t%v\n", instance.ImportTypeId)
+		fmt.Fprintf(w, "IMAGE:\t%v\n", instance.Image)
+		fmt.Fprintf(w, "KAFKA TOPIC:\t%v\n", instance.KafkaTopic)
+		fmt.Fprintf(w, "RESTART:\t%v\n", formatValue(instance.Restart))
+		fmt.Fprintf(w, "GENERATED:\t%v\n", instance.Generated)
+		fmt.Fprintf(w, "EXPECTED INTERVAL:\t%v\n", instance.ExpectedInterval)
+		fmt.Fprintf(w, "STALE:\t%v\n", instance.Stale)
+		fmt.Fprintf(w, "LABELS:\t%v\n", formatLabels(instance.Labels))
+		fmt.Fprintf(w, "CREATED:\t%v\n", instance.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "UPDATED:\t%v\n", instance.UpdatedAt.Format(time.RFC3339))
+		fmt.Fprintf(w, "VERSION:\t%v\n", instance.Version)
+		fmt.Fprintln(w, "CONFIGS:")
+		for _, config := range instance.Configs {
+			fmt.Fprintf(w, "  %v\t%v\n", config.Name, formatValue(config.Value))
+		}
+	})
+}
+
+func create(env env, args []string) error {
+	flags := newFlags(env, "create")
+	file := flags.String("f", "", "json or yaml file of the instance, - for stdin")
+	key := flags.String("idempotency-key", "", "key to retry the creation safely, random by default")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	instance := model.Instance{}
+	err = readInput(*file, &instance)
+	if err != nil {
+		return err
+	}
+	if *key != "" {
+		instance, err = env.client.CreateInstanceWithIdempotencyKey(env.ctx, env.token, instance, *key)
+	} else {
+		instance, err = env.client.CreateInstance(env.ctx, env.token, instance)
+	}
+	if err != nil {
+		return err
+	}
+	return printInstance(env, instance)
+}
+
+func update(env env, args []string) error {
+	flags := newFlags(env, "update")
+	file := flags.String("f", "", "json or yaml file of the complete instance, - for stdin; a version other than 0 is checked with If-Match")
+	name := flags.String("name", "", "new name")
+	sets := listFlag{}
+	flags.Var(&sets, "set", "config=value, values are parsed as json if possible, may be repeated")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 1); err != nil {
+		return err
+	}
+	if (*file != "") == (len(sets) > 0 || *name != "") {
+		return errors.New("use either -f or -set and -name")
+	}
+	var instance model.Instance
+	if *file != "" {
+		err = readInput(*file, &instance)
+		if err != nil {
+			return err
+		}
+		instance.Id = args[0]
+		if instance.Version != 0 {
+			instance, err = env.client.SetInstanceIfUnchanged(env.ctx, env.token, instance)
+		} else {
+			instance, err = env.client.SetInstance(env.ctx, env.token, instance)
+		}
+	} else {
+		patch := map[string]any{}
+		if *name != "" {
+			patch["name"] = *name
+		}
+		configs := map[string]any{}
+		for _, set := range sets {
+			key, value, ok := strings.Cut(set, "=")
+			if !ok {
+				return errors.New("-set has to be in the form config=value")
+			}
+			var parsed any
+			if json.Unmarshal([]byte(value), &parsed) != nil {
+				parsed = value
+			}
+			configs[key] = parsed
+		}
+		if len(configs) > 0 {
+			patch["configs"] = configs
+		}
+		instance, err = env.client.MergePatchInstance(env.ctx, env.token, args[0], patch)
+	}
+	if err != nil {
+		return err
+	}
+	return printInstance(env, instance)
+}
+
+func remove(env env, args []string) error {
+	flags := newFlags(env, "delete")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	deleted := []string{}
+	for _, id := range args {
+		err = env.client.DeleteInstance(env.ctx, env.token, id)
+		if err != nil {
+			break
+		}
+		deleted = append(deleted, id)
+	}
+	printErr := env.out.print(deleted, func(w io.Writer) {
+		for _, id := range deleted {
+			fmt.Fprintln(w, "deleted", id)
+		}
+	})
+	return errors.Join(err, printErr)
+}
+
+func count(env env, args []string) error {
+	flags := newFlags(env, "count")
+	getFilter := filterFlags(flags)
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	filter, err := getFilter()
+	if err != nil {
+		return err
+	}
+	result, err := env.client.CountInstances(env.ctx, env.token, client.ListOptions{Filter: filter})
+	if err != nil {
+		return err
+	}
+	return env.out.print(result, func(w io.Writer) {
+		fmt.Fprintln(w, result)
+	})
+}
+
+type instanceStatus struct {
+	Id               string           `json:"id"`
+	Name             string           `json:"name"`
+	ExpectedInterval string           `json:"expected_interval,omitempty"`
+	Stale            bool             `json:"stale"`
+	StaleSince       *time.Time       `json:"stale_since,omitempty"`
+	DataStats        *model.DataStats `json:"data_stats,omitempty"`
+}
+
+func status(env env, args []string) error {
+	flags := newFlags(env, "status")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	if len(args) == 0 {
+		health, ready, err := env.client.Ready(env.ctx)
+		if err != nil {
+			return err
+		}
+		err = env.out.print(health, func(w io.Writer) {
+			fmt.Fprintf(w, "SERVER\t%v\n", health.Status)
+			for _, name := range sortedKeys(health.Checks) {
+				check := health.Checks[name]
+				fmt.Fprintf(w, "%v\t%v\t%vms\t%v\n", name, check.Status, check.DurationMs, check.Error)
+			}
+		})
+		if err == nil && !ready {
+			err = errors.New("server not ready")
+		}
+		return err
+	}
+	instance, err := env.client.ReadInstance(env.ctx, env.token, args[0])
+	if err != nil {
+		return err
+	}
+	result := instanceStatus{Id: instance.Id, Name: instance.Name, ExpectedInterval: instance.ExpectedInterval, Stale: instance.Stale, StaleSince: instance.StaleSince}
+	stats, err := env.client.GetInstanceDataStats(env.ctx, env.token, instance.Id)
+	if err != nil {
+		fmt.Fprintln(env.stderr, "WARNING: unable to read data statistics:", err)
+	} else {
+		result.DataStats = &stats
+	}
+	return env.out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%v\n", result.Id)
+		fmt.Fprintf(w, "NAME:\t%v\n", result.Name)
+		fmt.Fprintf(w, "EXPECTED INTERVAL:\t%v\n", result.ExpectedInterval)
+		fmt.Fprintf(w, "STALE:\t%v\n", result.Stale)
+		if result.StaleSince != nil {
+			fmt.Fprintf(w, "STALE SINCE:\t%v\n", result.StaleSince.Format(time.RFC3339))
+		}
+		if result.DataStats != nil {
+			fmt.Fprintf(w, "MESSAGES LAST HOUR:\t%v\n", result.DataStats.MessagesLastHour)
+			fmt.Fprintf(w, "MESSAGES LAST DAY:\t%v\n", result.DataStats.MessagesLastDay)
+			if result.DataStats.LastMessageAt != nil {
+				fmt.Fprintf(w, "LAST MESSAGE:\t%v\n", result.DataStats.LastMessageAt.Format(time.RFC3339))
+			}
+		}
+	})
+}
+
+func logs(env env, args []string) error {
+	flags := newFlags(env, "logs")
+	tail := flags.Int64("tail", 0, "number of lines, 0 for the default of the server")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 1); err != nil {
+		return err
+	}
+	result, err := env.client.GetInstanceLogs(env.ctx, env.token, args[0], *tail)
+	if err != nil {
+		return err
+	}
+	if env.out.format == "table" {
+		// logs are printed as they are, a tabwriter would realign them
+		_, err = io.WriteString(env.out.out, result)
+		return err
+	}
+	return env.out.print(result, nil)
+}
+
+func apply(env env, args []string) error {
+	flags := newFlags(env, "apply")
+	file := flags.String("f", "", "json or yaml manifest, - for stdin")
+	dryRun := flags.Bool("dry-run", false, "only show the plan")
+	prune := flags.Bool("prune", false, "delete your instances, which are not part of the manifest; generated instances are kept")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	manifest := model.ApplyManifest{}
+	err = readInput(*file, &manifest)
+	if err != nil {
+		return err
+	}
+	result, err := env.client.Apply(env.ctx, env.token, manifest, model.ApplyOptions{DryRun: *dryRun, Prune: *prune})
+	if err != nil {
+		return err
+	}
+	err = env.out.print(result, func(w io.Writer) {
+		fmt.Fprintln(w, "ACTION\tKEY\tNAME\tINSTANCE\tCHANGED\tERROR")
+		for _, step := range result.Steps {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", step.Action, step.Key, step.Name, step.InstanceId, strings.Join(step.ChangedFields, ","), step.Error)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, step := range result.Steps {
+		if step.Error != "" {
+			if result.Applied {
+				return errors.New("some steps failed")
+			}
+			return errors.New("plan has errors, nothing was changed")
+		}
+	}
+	return nil
+}
+
+func export(env env, args []string) error {
+	flags := newFlags(env, "export")
+	getFilter := filterFlags(flags)
+	ids := listFlag{}
+	flags.Var(&ids, "id", "export only this instance, may be repeated")
+	includeSecrets := flags.Bool("include-secrets", false, "export configs matching the secret pattern of the server")
+	bundle := flags.Bool("bundle", false, "export all instances as bundle for import instead of a manifest for apply")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	filter, err := getFilter()
+	if err != nil {
+		return err
+	}
+	result, err := env.client.ExportInstances(env.ctx, env.token, filter, ids, *includeSecrets)
+	if err != nil {
+		return err
+	}
+	out := env.out
+	if out.format == "table" {
+		out.format = "yaml"
+	}
+	if *bundle {
+		return out.print(result, nil)
+	}
+	manifest := model.ApplyManifest{Instances: []model.ManifestInstance{}}
+	skipped := 0
+	for _, definition := range result.Instances {
+		key := definition.Labels[model.ApplyKeyLabel]
+		if key == "" {
+			skipped++
+			continue
+		}
+		if len(definition.OmittedConfigs) > 0 {
+			fmt.Fprintf(env.stderr, "WARNING: %v: omitted %v; applying the manifest resets them to their defaults\n", key, strings.Join(definition.OmittedConfigs, ", "))
+		}
+		labels := maps.Clone(definition.Labels)
+		delete(labels, model.ApplyKeyLabel)
+		if len(labels) == 0 {
+			labels = nil
+		}
+		manifest.Instances = append(manifest.Instances, model.ManifestInstance{
+			Key:              key,
+			Name:             definition.Name,
+			ImportTypeId:     definition.ImportTypeId,
+			Configs:          definition.Configs,
+			Restart:          definition.Restart,
+			ExpectedInterval: definition.ExpectedInterval,
+			Labels:           labels,
+		})
+	}
+	if skipped > 0 {
+		fmt.Fprintf(env.stderr, "WARNING: skipped %v instances without %v label, use -bundle to export them\n", skipped, model.ApplyKeyLabel)
+	}
+	return out.print(manifest, nil)
+}
+
+func importBundle(env env, args []string) error {
+	flags := newFlags(env, "import")
+	file := flags.String("f", "", "json or yaml bundle, - for stdin")
+	dryRun := flags.Bool("dry-run", false, "only validate and show the planned actions")
+	onConflict := flags.String("on-conflict", "", "handling of existing instances with the same name: fail (default), skip, update or create")
+	mappings := listFlag{}
+	flags.Var(&mappings, "import-type-map", "old:new, replaces an import type id of the bundle, may be repeated")
+	args, err := parseArgs(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(flags, args, 0); err != nil {
+		return err
+	}
+	options := model.ImportOptions{DryRun: *dryRun, OnConflict: model.OnConflict(*onConflict), ImportTypeMapping: map[string]string{}}
+	for _, mapping := range mappings {
+		from, to, ok := strings.Cut(mapping, ":")
+		if !ok {
+			return errors.New("-import-type-map has to be in the form old:new")
+		}
+		options.ImportTypeMapping[from] = to
+	}
+	bundle := model.InstanceBundle{}
+	err = readInput(*file, &bundle)
+	if err != nil {
+		return err
+	}
+	result, err := env.client.ImportInstances(env.ctx, env.token, bundle, options)
+	if err != nil {
+		return err
+	}
+	err = env.out.print(result, func(w io.Writer) {
+		fmt.Fprintln(w, "ACTION\tNAME\tIMPORT TYPE\tINSTANCE\tERROR\tWARNINGS")
+		for _, r := range result.Results {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", r.Action, r.Name, r.ImportTypeId, r.InstanceId, r.Error, strings.Join(r.Warnings, "; "))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range result.Results {
+		if r.Action == model.ImportActionError {
+			return errors.New("some instances failed")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// importctl manages import instances with the /v2 api of import-deploy.
+//
+// The url and token are taken from the flags -url and -token, the environment variables IMPORTCTL_URL and IMPORTCTL_TOKEN
+// or the config file (default $XDG_CONFIG_HOME/importctl/config.json), in this order:
+//
+//	{"url": "https://api.example.com/import-deploy", "token": "..."}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	client "github.com/SENERGY-Platform/import-deploy/lib/client/v2"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+const defaultUrl = "http://localhost:8080"
+
+type settings struct {
+	Url   string `json:"url"`
+	Token string `json:"token"`
+}
+
+// env holds everything a command needs
+type env struct {
+	ctx    context.Context
+	client *client.Client
+	token  jwt.Token
+	out    printer
+	stderr io.Writer // usage and warnings
+}
+
+type command struct {
+	usage       string
+	description string
+	run         func(env env, args []string) error
+}
+
+var commands = map[string]command{}
+
+var commandOrder = []string{"list", "get", "create", "update", "delete", "count", "status", "logs", "apply", "export", "import"}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("importctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { usage(flags, stderr) }
+	url := flags.String("url", "", "api url, overrides IMPORTCTL_URL and the config file")
+	token := flags.String("token", "", "token, overrides IMPORTCTL_TOKEN and the config file")
+	configFile := flags.String("config", "", "config file with url and token (default $XDG_CONFIG_HOME/importctl/config.json)")
+	output := flags.String("o", "table", "output format: table, json or yaml")
+	forUser := flags.String("for-user", "", "admins only: act on behalf of this user id, * lists and counts the instances of all users")
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		usage(flags, stderr)
+		return 2
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintln(stderr, "unknown command", flags.Arg(0))
+		usage(flags, stderr)
+		return 2
+	}
+	out, err := newPrinter(*output, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	conf, err := loadSettings(*configFile, *url, *token)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	c := client.New(conf.Url)
+	if *forUser != "" {
+		c = c.ForUser(*forUser)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	err = cmd.run(env{ctx: ctx, client: c, token: jwt.Token{Token: conf.Token}, out: out, stderr: stderr}, flags.Args()[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "ERROR:", err)
+		return 1
+	}
+	return 0
+}
+
+func usage(flags *flag.FlagSet, stderr io.Writer) {
+	fmt.Fprintln(stderr, "usage: importctl [flags] <command> [command flags] [args]")
+	fmt.Fprintln(stderr, "\ncommands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(stderr, "  %-8s %s\n", name, commands[name].description)
+	}
+	fmt.Fprintln(stderr, "\nflags:")
+	flags.PrintDefaults()
+}
+
+// loadSettings applies the config file, the environment and the flags in this order
+func loadSettings(configFile string, url string, token string) (result settings, err error) {
+	result.Url = defaultUrl
+	explicit := configFile != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err == nil {
+			configFile = filepath.Join(dir, "importctl", "config.json")
+		}
+	}
+	if configFile != "" {
+		file, err := os.ReadFile(configFile)
+		if err != nil && (explicit || !errors.Is(err, os.ErrNotExist)) {
+			return result, err
+		}
+		if err == nil {
+			err = json.Unmarshal(file, &result)
+			if err != nil {
+				return result, fmt.Errorf("invalid config file %v: %w", configFile, err)
+			}
+		}
+	}
+	if value := os.Getenv("IMPORTCTL_URL"); value != "" {
+		result.Url = value
+	}
+	if value := os.Getenv("IMPORTCTL_TOKEN"); value != "" {
+		result.Token = value
+	}
+	if url != "" {
+		result.Url = url
+	}
+	if token != "" {
+		result.Token = token
+	}
+	return result, nil
+}
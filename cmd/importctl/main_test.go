@@ -0,0 +1,323 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/api"
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/controller"
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func TestRunList(t *testing.T) {
+	server := newTestServer(t, config.Config{})
+	server.add(t, model.Instance{Id: "a", Name: "b", Owner: "user1"})
+	server.add(t, model.Instance{Id: "b", Name: "a", Owner: "user1"})
+	server.add(t, model.Instance{Id: "c", Name: "c", Owner: "user2"})
+
+	stdout, stderr, code := server.run(t, "user1", "-o", "json", "list")
+	if code != 0 {
+		t.Fatal(code, stderr)
+	}
+	instances := []model.Instance{}
+	err := json.Unmarshal([]byte(stdout), &instances)
+	if err != nil {
+		t.Fatal(err, stdout)
+	}
+	ids := []string{}
+	for _, instance := range instances {
+		ids = append(ids, instance.Id)
+	}
+	if !slices.Equal(ids, []string{"b", "a"}) {
+		t.Error(ids)
+	}
+
+	stdout, stderr, code = server.run(t, "user1", "count")
+	if code != 0 || stdout != "2\n" {
+		t.Error(code, stdout, stderr)
+	}
+}
+
+func TestRunGet(t *testing.T) {
+	server := newTestServer(t, config.Config{})
+	server.add(t, model.Instance{Id: "a", Name: "first", Owner: "user1"})
+
+	stdout, stderr, code := server.run(t, "user1", "get", "a")
+	if code != 0 || !strings.Contains(stdout, "first") {
+		t.Error(code, stdout, stderr)
+	}
+
+	_, stderr, code = server.run(t, "user2", "get", "a")
+	if code != 1 || !strings.Contains(stderr, "ERROR") {
+		t.Error(code, stderr)
+	}
+}
+
+func TestRunStatus(t *testing.T) {
+	server := newTestServer(t, config.Config{})
+	server.add(t, model.Instance{Id: "a", Name: "a", Owner: "user1", KafkaTopic: "topic-a"})
+
+	stdout, stderr, code := server.run(t, "user1", "-o", "json", "status", "a")
+	if code != 0 {
+		t.Fatal(code, stderr)
+	}
+	result := instanceStatus{}
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatal(err, stdout)
+	}
+	if result.Id != "a" || result.DataStats == nil || result.DataStats.MessagesLastHour != 1 {
+		t.Error(stdout)
+	}
+}
+
+func TestRunLogs(t *testing.T) {
+	server := newTestServer(t, config.Config{LogsMaxLines: 3})
+	server.add(t, model.Instance{Id: "a", Name: "a", Owner: "user1", ServiceId: "service-a"})
+
+	stdout, stderr, code := server.run(t, "user1", "logs", "a", "-tail", "5")
+	if code != 0 {
+		t.Fatal(code, stderr)
+	}
+	if stdout != "line1\tvalue\nline2\n" {
+		t.Errorf("%q", stdout)
+	}
+	if !slices.Equal(server.deploy.tails, []int64{3}) {
+		t.Error("tail not limited by LogsMaxLines", server.deploy.tails)
+	}
+
+	stdout, stderr, code = server.run(t, "user1", "-o", "json", "logs", "a")
+	if code != 0 || stdout != "\"line1\\tvalue\\nline2\\n\"\n" {
+		t.Errorf("%v %q %v", code, stdout, stderr)
+	}
+
+	_, stderr, code = server.run(t, "user2", "logs", "a")
+	if code != 1 || !strings.Contains(stderr, "ERROR") {
+		t.Error(code, stderr)
+	}
+
+	_, stderr, code = server.run(t, "user1", "logs")
+	if code != 2 || !strings.Contains(stderr, "usage: importctl logs") {
+		t.Error(code, stderr)
+	}
+}
+
+func TestRunDelete(t *testing.T) {
+	server := newTestServer(t, config.Config{})
+	server.add(t, model.Instance{Id: "a", Name: "a", Owner: "user1", ServiceId: "service-a"})
+	server.add(t, model.Instance{Id: "b", Name: "b", Owner: "user1", ServiceId: "service-b"})
+
+	stdout, stderr, code := server.run(t, "user1", "delete", "a")
+	if code != 0 || stdout != "deleted a\n" {
+		t.Error(code, stdout, stderr)
+	}
+	if !slices.Equal(server.deploy.removed, []string{"service-a"}) {
+		t.Error(server.deploy.removed)
+	}
+	if _, ok := server.db.instances["a"]; ok {
+		t.Error("instance not removed")
+	}
+
+	_, _, code = server.run(t, "user2", "delete", "b")
+	if code != 1 {
+		t.Error(code)
+	}
+	if _, ok := server.db.instances["b"]; !ok {
+		t.Error("instance removed without administrate right")
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	server := newTestServer(t, config.Config{})
+	_, stderr, code := server.run(t, "user1")
+	if code != 2 || !strings.Contains(stderr, "logs") {
+		t.Error(code, stderr)
+	}
+	_, stderr, code = server.run(t, "user1", "unknown")
+	if code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Error(code, stderr)
+	}
+}
+
+type testServer struct {
+	url    string
+	db     *fakeDatabase
+	deploy *fakeDeploy
+	perm   permV2Client.Client
+}
+
+// newTestServer serves the api with a controller, whose database, deployment backend and kafka are kept in memory
+func newTestServer(t *testing.T, conf config.Config) *testServer {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("IMPORTCTL_URL", "")
+	t.Setenv("IMPORTCTL_TOKEN", "")
+	perm, err := permV2Client.NewTestClient(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err, _ = perm.SetTopic(permV2Client.InternalAdminToken, permV2Client.Topic{Id: model.PermV2InstanceTopic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := &fakeDatabase{instances: map[string]model.Instance{}}
+	deployment := &fakeDeploy{}
+	m := metrics.New()
+	control := controller.New(conf, db, deployment, fakeKafka{}, perm, nil, nil, m)
+	handler, err := api.NewHandler(conf, control, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &testServer{url: server.URL, db: db, deploy: deployment, perm: perm}
+}
+
+func (this *testServer) add(t *testing.T, instance model.Instance) {
+	this.db.instances[instance.Id] = instance
+	_, err, _ := this.perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permV2Client.ResourcePermissions{
+		UserPermissions: map[string]permV2Client.PermissionsMap{instance.Owner: {Read: true, Write: true, Execute: true, Administrate: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// run executes importctl as the user and returns its output and exit code
+func (this *testServer) run(t *testing.T, userId string, args ...string) (stdout string, stderr string, code int) {
+	t.Helper()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	code = run(append([]string{"-url", this.url, "-token", testToken(userId)}, args...), out, errOut)
+	return out.String(), errOut.String(), code
+}
+
+// testToken returns an unsigned token; the api parses tokens without validation
+func testToken(userId string) string {
+	encode := func(value any) string {
+		b, _ := json.Marshal(value)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	return encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." +
+		encode(map[string]any{"sub": userId, "realm_access": map[string][]string{"roles": {"user"}}}) + ".c2lnbmF0dXJl"
+}
+
+// fakeDatabase keeps instances in memory; users access their own instances.
+// Methods not needed by the tests are not implemented and panic.
+type fakeDatabase struct {
+	controller.Database
+	mux       sync.Mutex
+	instances map[string]model.Instance
+}
+
+func (this *fakeDatabase) Transaction(ctx context.Context) (context.Context, func(success bool) error, error) {
+	return ctx, func(bool) error { return nil }, nil
+}
+
+func (this *fakeDatabase) ListInstances(_ context.Context, limit int64, offset int64, _ string, jwt jwt.Token, _ bool, _ model.InstanceFilter, _ *model.InstanceCursor) ([]model.Instance, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	result := []model.Instance{}
+	for _, instance := range this.instances {
+		if instance.Owner == jwt.GetUserId() {
+			result = append(result, instance)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.Instance) int { return strings.Compare(a.Name, b.Name) })
+	result = result[min(offset, int64(len(result))):]
+	return result[:min(limit, int64(len(result)))], nil
+}
+
+func (this *fakeDatabase) CountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (int64, error) {
+	result, err := this.ListInstances(ctx, int64(len(this.instances)), 0, "", jwt, true, filter, nil)
+	return int64(len(result)), err
+}
+
+func (this *fakeDatabase) GetInstance(_ context.Context, id string, jwt jwt.Token) (model.Instance, bool, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok := this.instances[id]
+	if !ok || instance.Owner != jwt.GetUserId() {
+		return model.Instance{}, false, errors.New("requested instance nonexistent")
+	}
+	return instance, true, nil
+}
+
+func (this *fakeDatabase) SetInstanceVersion(_ context.Context, id string, previousVersion int64, version int64) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok := this.instances[id]
+	if !ok || instance.Version != previousVersion {
+		return model.ErrVersionConflict
+	}
+	instance.Version = version
+	this.instances[id] = instance
+	return nil
+}
+
+func (this *fakeDatabase) RemoveInstance(_ context.Context, id string, _ jwt.Token) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	delete(this.instances, id)
+	return nil
+}
+
+// fakeDeploy records removed containers and the requested log lines
+type fakeDeploy struct {
+	deploy.DeploymentClient
+	removed []string
+	tails   []int64
+}
+
+func (this *fakeDeploy) RemoveContainer(_ context.Context, id string) error {
+	this.removed = append(this.removed, id)
+	return nil
+}
+
+func (this *fakeDeploy) ContainerLogs(_ context.Context, _ string, _ *bool, tail int64) (string, error) {
+	this.tails = append(this.tails, tail)
+	return "line1\tvalue\nline2\n", nil
+}
+
+type fakeKafka struct {
+	controller.KafkaAdmin
+}
+
+func (fakeKafka) DeleteTopic(context.Context, string) error {
+	return nil
+}
+
+func (fakeKafka) GetDataStats(_ context.Context, topics []string) (map[string]model.DataStats, error) {
+	result := map[string]model.DataStats{}
+	for _, topic := range topics {
+		result[topic] = model.DataStats{MessagesLastHour: 1, MessagesLastDay: 2}
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+)
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (printer, error) {
+	switch format {
+	case "table", "json", "yaml":
+		return printer{format: format, out: out}, nil
+	}
+	return printer{}, errors.New("unknown output format " + format)
+}
+
+// print writes value as json or yaml, or calls table with a tabwriter, whose columns are separated by '\t'
+func (this printer) print(value any, table func(w io.Writer)) error {
+	switch this.format {
+	case "json":
+		encoder := json.NewEncoder(this.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "yaml":
+		b, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = this.out.Write(b)
+		return err
+	}
+	w := tabwriter.NewWriter(this.out, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+// readInput reads json or yaml from the file, "-" reads stdin
+func readInput(file string, value any) error {
+	if file == "" {
+		return errors.New("missing -f")
+	}
+	var b []byte
+	var err error
+	if file == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(b, value)
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func formatLabels(labels map[string]string) string {
+	result := []string{}
+	for key, value := range labels {
+		result = append(result, key+":"+value)
+	}
+	slices.Sort(result)
+	return strings.Join(result, ",")
+}
+
+// listFlag collects repeated flags
+type listFlag []string
+
+func (this *listFlag) String() string {
+	return strings.Join(*this, ",")
+}
+
+func (this *listFlag) Set(value string) error {
+	*this = append(*this, value)
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, AdminEndpoints)
+
+	document(http.MethodGet, "/admin/instances", operation{
+		Summary:     "list the instances of all users",
+		Description: "admins only",
+		Query: slices.Concat([]parameter{limitParameter, offsetParameter, cursorParameter, sortParameter}, instanceFilterParameters, []parameter{
+			ownerParameter,
+			{Name: "include_data_stats", Description: "add data statistics of the kafka topics", Type: "boolean"},
+		}),
+		Response: model.AdminInstanceList{},
+	})
+	document(http.MethodPost, "/admin/users/:id/transfer", operation{
+		Summary:     "change the owner of all instances of the user",
+		Description: "admins only; failed transfers are reported in errors and do not stop the remaining transfers",
+		Body:        model.TransferRequest{},
+		Response:    model.TransferResult{},
+	})
+}
+
+func AdminEndpoints(config config.Config, control Controller, router Router) {
+	resource := "/admin/instances"
+
+	router.GET(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !token.IsAdmin() {
+			http.Error(writer, "only allowed for admins", http.StatusForbidden)
+			return
+		}
+		limit, offset, err := getLimitOffset(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orderBy, asc, err := getSort(request, true)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter, err := getInstanceFilter(request, true)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		after, err := getCursor(config, request, orderBy, asc, offset)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		includeDataStats := strings.ToLower(request.URL.Query().Get("include_data_stats")) == "true"
+
+		instances, err, errCode := control.AdminListInstances(request.Context(), token, limit, offset, orderBy, asc, filter, after, includeDataStats)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		total, err, errCode := control.AdminCountInstances(request.Context(), token, filter)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		next, err := nextCursor(config, orderBy, asc, limit, instances)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result := model.AdminInstanceList{Total: total, Instances: []model.AdminInstance{}, NextCursor: next}
+		for _, instance := range instances {
+			result.Instances = append(result.Instances, model.AdminInstance{Instance: instance, Owner: instance.Owner})
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+	router.POST("/admin/users/:id/transfer", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		transfer := model.TransferRequest{}
+		err = json.NewDecoder(request.Body).Decode(&transfer)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := control.AdminTransferInstances(request.Context(), params.ByName("id"), transfer.NewOwner, token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+}
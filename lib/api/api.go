@@ -17,7 +17,11 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"reflect"
 	"runtime"
@@ -27,28 +31,72 @@ import (
 
 	"github.com/SENERGY-Platform/import-deploy/lib/api/util"
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
 	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-var endpoints = []func(config config.Config, control Controller, router *httprouter.Router){}
+var endpoints = []func(config config.Config, control Controller, router Router){}
 
-func Start(config config.Config, control Controller) (err error) {
+// Start listens in the background. The caller is responsible to call Shutdown on the returned server.
+func Start(config config.Config, control Controller, m *metrics.Metrics) (server *http.Server, err error) {
 	log.Println("start api")
+	handler, err := NewHandler(config, control, m)
+	if err != nil {
+		return nil, err
+	}
+	server = &http.Server{Addr: ":" + config.ServerPort, Handler: handler}
+	log.Println("listen on port", config.ServerPort)
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Println("ERROR: api server stopped", err)
+		}
+	}()
+	return server, nil
+}
+
+// NewHandler returns the handler of all endpoints, used by Start and by tests with httptest
+func NewHandler(config config.Config, control Controller, m *metrics.Metrics) (handler http.Handler, err error) {
 	router := httprouter.New()
 	log.Println("add heart beat endpoint")
 	router.GET("/", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		writer.WriteHeader(http.StatusOK)
 	})
+	log.Println("add metrics endpoint")
+	router.Handler(http.MethodGet, "/metrics", m.Handler())
+	instrumented := &instrumentedRouter{router: router, metrics: m}
 	for _, e := range endpoints {
 		log.Println("add endpoints: " + runtime.FuncForPC(reflect.ValueOf(e).Pointer()).Name())
-		e(config, control, router)
+		e(config, control, instrumented)
+	}
+	log.Println("add openapi documentation")
+	spec, err := buildOpenApi(instrumented.routes)
+	if err != nil {
+		return nil, err
 	}
+	router.GET("/openapi.json", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writer.Write(spec)
+	})
+	router.GET("/docs", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		writer.Write([]byte(swaggerUi))
+	})
+	assets, err := fs.Sub(swaggerUiAssets, "swagger-ui")
+	if err != nil {
+		return nil, err
+	}
+	router.Handler(http.MethodGet, "/docs/:file", http.StripPrefix("/docs/", http.FileServer(http.FS(assets))))
 	log.Println("add logging and cors")
 	corsHandler := util.NewCors(router)
 	logger := accesslog.New(corsHandler)
-	log.Println("listen on port", config.ServerPort)
-	go func() { log.Println(http.ListenAndServe(":"+config.ServerPort, logger)) }()
-	return nil
+	log.Println("add tracing")
+	return otelhttp.NewHandler(logger, "import-deploy"), nil
 }
 
 func getToken(request *http.Request) (token jwt.Token, err error) {
@@ -58,3 +106,27 @@ func getToken(request *http.Request) (token jwt.Token, err error) {
 	}
 	return token, nil
 }
+
+// allUsers as for_user value lets admins list and count the instances of all users
+const allUsers = "*"
+
+// getUserToken returns the token of the request or, if an admin sets the for_user query parameter, the token of that user.
+// The returned context records the admin as performer of the request.
+// With allowAllUsers, for_user=* returns the token of the request; the caller is responsible to handle it.
+func getUserToken(control Controller, request *http.Request, allowAllUsers bool) (ctx context.Context, token jwt.Token, err error, code int) {
+	token, err = getToken(request)
+	if err != nil {
+		return request.Context(), token, err, http.StatusBadRequest
+	}
+	forUser := request.URL.Query().Get("for_user")
+	if forUser == "" {
+		return request.Context(), token, nil, http.StatusOK
+	}
+	if forUser == allUsers {
+		if !allowAllUsers {
+			return request.Context(), token, errors.New("for_user=" + allUsers + " is only supported to list and count instances"), http.StatusBadRequest
+		}
+		return request.Context(), token, nil, http.StatusOK
+	}
+	return control.ImpersonateUser(request.Context(), token, forUser)
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, ApplyEndpoints)
+
+	document(http.MethodPost, "/apply", operation{
+		Summary: "create, update and delete instances of the user to match the manifest",
+		Description: "instances are matched by the key of the manifest entries, stored in the label " + model.ApplyKeyLabel +
+			"; the plan is only executed if no step has an error; the body may be yaml if the Content-Type is application/yaml",
+		Query: []parameter{
+			{Name: "dry_run", Description: "only return the plan", Type: "boolean"},
+			{Name: "prune", Description: "delete instances of the user, which are not part of the manifest; generated instances are kept", Type: "boolean"},
+			{Name: "for_user", Description: "admins only: act on behalf of this user id", Type: "string"},
+		},
+		Body:     model.ApplyManifest{},
+		Response: model.ApplyResult{},
+	})
+}
+
+func ApplyEndpoints(_ config.Config, control Controller, router Router) {
+	router.POST("/apply", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := request.URL.Query()
+		options := model.ApplyOptions{
+			DryRun: strings.ToLower(query.Get("dry_run")) == "true",
+			Prune:  strings.ToLower(query.Get("prune")) == "true",
+		}
+		manifest := model.ApplyManifest{}
+		err := decodeJsonOrYaml(request, &manifest)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		result, err, code := control.ApplyManifest(ctx, token, manifest, options)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+}
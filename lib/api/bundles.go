@@ -0,0 +1,167 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/julienschmidt/httprouter"
+	"sigs.k8s.io/yaml"
+)
+
+var yamlContentTypes = []string{"application/yaml", "application/x-yaml", "text/yaml"}
+
+func init() {
+	endpoints = append(endpoints, BundleEndpoints)
+
+	forUserParameter := parameter{Name: "for_user", Description: "admins only: act on behalf of this user id", Type: "string"}
+	document(http.MethodGet, "/export/instances", operation{
+		Summary:     "export the definitions of instances",
+		Description: "configs matching the secret_config_pattern are omitted and listed in omitted_configs, unless include_secrets=true",
+		Query: slices.Concat(instanceFilterParameters, []parameter{
+			{Name: "id", Description: "export only these instances, may be repeated", Type: "string"},
+			{Name: "include_secrets", Description: "export secret configs", Type: "boolean"},
+			{Name: "format", Description: "json (default) or yaml", Type: "string"},
+			forUserParameter,
+		}),
+		Response: model.InstanceBundle{},
+	})
+	document(http.MethodPost, "/import/instances", operation{
+		Summary:     "create or update instances from exported definitions",
+		Description: "instances of the user with the same name are conflicts; the body may be yaml if the Content-Type is application/yaml",
+		Query: []parameter{
+			{Name: "dry_run", Description: "only validate and report the planned actions", Type: "boolean"},
+			{Name: "on_conflict", Description: "fail (default), skip, update or create", Type: "string"},
+			{Name: "import_type_map", Description: "replace import type ids of the bundle, as old:new, may be repeated", Type: "string"},
+			forUserParameter,
+		},
+		Body:     model.InstanceBundle{},
+		Response: model.ImportReport{},
+	})
+}
+
+// BundleEndpoints move instance definitions between installations.
+// The routes are not below /instances, because httprouter does not allow static segments next to /instances/:id.
+func BundleEndpoints(_ config.Config, control Controller, router Router) {
+	router.GET("/export/instances", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := request.URL.Query()
+		format := query.Get("format")
+		if format != "" && format != "json" && format != "yaml" {
+			http.Error(writer, "unknown format", http.StatusBadRequest)
+			return
+		}
+		filter, err := getInstanceFilter(request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		result, err, code := control.ExportInstances(ctx, token, filter, query["id"], strings.ToLower(query.Get("include_secrets")) == "true")
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if format == "yaml" {
+			body, err := yaml.Marshal(result)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writer.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+			_, err = writer.Write(body)
+			if err != nil {
+				log.Println("ERROR: unable to write response", err)
+			}
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.POST("/import/instances", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := request.URL.Query()
+		options := model.ImportOptions{
+			DryRun:            strings.ToLower(query.Get("dry_run")) == "true",
+			OnConflict:        model.OnConflict(query.Get("on_conflict")),
+			ImportTypeMapping: map[string]string{},
+		}
+		for _, mapping := range query["import_type_map"] {
+			from, to, ok := strings.Cut(mapping, ":")
+			if !ok || from == "" || to == "" {
+				http.Error(writer, "import_type_map has to be in the form old:new", http.StatusBadRequest)
+				return
+			}
+			options.ImportTypeMapping[from] = to
+		}
+		bundle := model.InstanceBundle{}
+		err := decodeJsonOrYaml(request, &bundle)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		result, err, code := control.ImportInstances(ctx, token, bundle, options)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+}
+
+// decodeJsonOrYaml reads the body as yaml if the Content-Type is one of yamlContentTypes, as json otherwise
+func decodeJsonOrYaml(request *http.Request, value any) (err error) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return err
+	}
+	contentType := request.Header.Get("Content-Type")
+	if contentType != "" {
+		contentType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return errors.New("invalid Content-Type header: " + err.Error())
+		}
+	}
+	if slices.Contains(yamlContentTypes, contentType) {
+		return yaml.Unmarshal(body, value)
+	}
+	return json.Unmarshal(body, value)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package api
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+func TestCursorIsEncrypted(t *testing.T) {
+	conf := config.Config{CursorSecret: "secret"}
+	results := []model.Instance{{Id: "id1", Owner: "owner1"}}
+	next, err := nextCursor(conf, "owner", true, 1, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := base64.RawURLEncoding.DecodeString(next)
+	if strings.Contains(string(plain), "owner1") || strings.Contains(string(plain), "id1") {
+		t.Error("cursor leaks the last instance", string(plain))
+	}
+
+	request := httptest.NewRequest("GET", "/admin/instances?cursor="+next, nil)
+	cursor, err := getCursor(conf, request, "owner", true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Value != "owner1" || cursor.Id != "id1" {
+		t.Error(cursor)
+	}
+
+	_, err = getCursor(config.Config{CursorSecret: "other"}, request, "owner", true, 0)
+	if err == nil {
+		t.Error("cursor accepted with another secret")
+	}
+	tampered := httptest.NewRequest("GET", "/admin/instances?cursor="+next[:len(next)-2]+"AA", nil)
+	_, err = getCursor(conf, tampered, "owner", true, 0)
+	if err == nil {
+		t.Error("tampered cursor accepted")
+	}
+	_, err = getCursor(conf, request, "name", true, 0)
+	if err == nil {
+		t.Error("cursor accepted for another sort")
+	}
+}
+
+func TestOwnerOnlyForAllUsers(t *testing.T) {
+	request := httptest.NewRequest("GET", "/instances?sort=owner.desc&owner=user1", nil)
+	_, _, err := getSort(request, false)
+	if err == nil {
+		t.Error("sort by owner accepted for own instances")
+	}
+	_, err = getInstanceFilter(request, false)
+	if err == nil {
+		t.Error("owner filter accepted for own instances")
+	}
+	orderBy, asc, err := getSort(request, true)
+	if err != nil || orderBy != "owner" || asc {
+		t.Error(orderBy, asc, err)
+	}
+	filter, err := getInstanceFilter(request, true)
+	if err != nil || filter.Owner != "user1" {
+		t.Error(filter, err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, HealthEndpoints)
+
+	document(http.MethodGet, "/health/live", operation{Summary: "liveness", Response: model.Health{}, Public: true})
+	document(http.MethodGet, "/health/ready", operation{
+		Summary:     "readiness",
+		Description: "responds with 503 if a dependency check fails",
+		Response:    model.Health{},
+		Public:      true,
+	})
+}
+
+func HealthEndpoints(_ config.Config, control Controller, router Router) {
+	resource := "/health"
+
+	// live only reports that the process is able to handle requests; dependencies are checked by ready
+	router.GET(resource+"/live", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err := json.NewEncoder(writer).Encode(model.Health{Status: model.HealthOk})
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.GET(resource+"/ready", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		result, ready := control.CheckReadiness(request.Context())
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if !ready {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+		err := json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+}
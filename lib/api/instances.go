@@ -17,30 +17,94 @@
 package api
 
 import (
+	"crypto/rand"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"mime"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
 	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 	"github.com/julienschmidt/httprouter"
 )
 
 func init() {
 	endpoints = append(endpoints, InstancesEndpoints)
+
+	forUserParameter := parameter{Name: "for_user", Description: "admins only: act on behalf of this user id", Type: "string"}
+	forAllUsersParameter := parameter{Name: "for_user", Description: "admins only: act on behalf of this user id, * for all users", Type: "string"}
+	document(http.MethodGet, "/instances", operation{
+		Summary:     "list instances",
+		Description: "if more instances may follow, the cursor of the next page is returned in the X-Next-Cursor header",
+		Query: slices.Concat([]parameter{limitParameter, offsetParameter, cursorParameter, sortParameter}, instanceFilterParameters, []parameter{
+			{Name: "include_data_stats", Description: "add data statistics of the kafka topics", Type: "boolean"},
+			forAllUsersParameter,
+			ownerParameter,
+		}),
+		Response: []model.Instance{},
+	})
+	document(http.MethodGet, "/total/instances", operation{
+		Summary:             "count instances",
+		Query:               append(slices.Clone(instanceFilterParameters), forAllUsersParameter, ownerParameter),
+		Response:            int64(0),
+		ResponseContentType: "application/txt",
+	})
+	document(http.MethodGet, "/instances/:id", operation{Summary: "read instance", Description: "the ETag header contains the version", Query: []parameter{forUserParameter}, Response: model.Instance{}})
+	document(http.MethodGet, "/instances/:id/data-stats", operation{Summary: "read data statistics of the instance topic", Query: []parameter{forUserParameter}, Response: model.DataStats{}})
+	document(http.MethodGet, "/instances/:id/preview", operation{
+		Summary:  "read the latest messages of the instance topic",
+		Query:    []parameter{{Name: "n", Description: "number of messages, defaults to 10", Type: "integer"}, forUserParameter},
+		Response: []model.PreviewRecord{},
+	})
+	document(http.MethodGet, "/instances/:id/logs", operation{
+		Summary:             "read the latest log lines of the instance container",
+		Query:               []parameter{{Name: "tail", Description: "number of lines, defaults to 100", Type: "integer"}, forUserParameter},
+		Response:            "",
+		ResponseContentType: "text/plain",
+	})
+	document(http.MethodDelete, "/instances/:id", operation{Summary: "delete instance", Description: ifMatchDescription, Query: []parameter{forUserParameter}, Status: http.StatusNoContent})
+	document(http.MethodPut, "/instances/:id", operation{Summary: "update instance", Description: ifMatchDescription, Query: []parameter{forUserParameter}, Body: model.Instance{}})
+	document(http.MethodPatch, "/instances/:id", operation{
+		Summary:     "update name, configs, restart and labels of the instance",
+		Description: patchDescription + "; " + ifMatchDescription,
+		Query:       []parameter{forUserParameter},
+		Body:        map[string]any{},
+		Response:    model.Instance{},
+	})
+	document(http.MethodPost, "/instances", operation{Summary: "create instance", Description: idempotencyKeyDescription, Body: model.Instance{}, Response: model.Instance{}})
+	document(http.MethodPost, "/instances/:id/transfer", operation{
+		Summary:     "change the owner of the instance",
+		Description: "requires the administrate right; the permissions of the previous owner are moved to the new owner",
+		Query:       []parameter{forUserParameter},
+		Body:        model.TransferRequest{},
+	})
+	document(http.MethodGet, "/instances/:id/permissions", operation{
+		Summary:     "read the user, group and role permissions of the instance",
+		Description: "requires the administrate right",
+		Query:       []parameter{forUserParameter},
+		Response:    permV2Client.ResourcePermissions{},
+	})
+	document(http.MethodPut, "/instances/:id/permissions", operation{
+		Summary:     "replace the user, group and role permissions of the instance",
+		Description: "requires the administrate right; the owner has to keep all rights",
+		Query:       []parameter{forUserParameter},
+		Body:        permV2Client.ResourcePermissions{},
+		Response:    permV2Client.ResourcePermissions{},
+	})
 }
 
-func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.Router) {
+func InstancesEndpoints(config config.Config, control Controller, router Router) {
 	resource := "/instances"
 
 	router.GET(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		token, err := getToken(request)
-		if err != nil {
-			http.Error(writer, err.Error(), http.StatusBadRequest)
-			return
-		}
 		limit := request.URL.Query().Get("limit")
 		if limit == "" {
 			limit = "100"
@@ -59,21 +123,43 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		sort := request.URL.Query().Get("sort")
-		if sort == "" {
-			sort = "name"
+		forAllUsers := request.URL.Query().Get("for_user") == allUsers
+		orderBy, asc, err := getSort(request, forAllUsers)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
 		}
-		orderBy := strings.Split(sort, ".")[0]
-		asc := !strings.HasSuffix(sort, ".desc")
 
-		search := request.URL.Query().Get("search")
-
-		includeGenerated := strings.ToLower(request.URL.Query().Get("exclude_generated")) != "true"
-		results, err, errCode := control.ListInstances(token, limitInt, offsetInt, orderBy, asc, search, includeGenerated)
+		filter, err := getInstanceFilter(request, forAllUsers)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		after, err := getCursor(config, request, orderBy, asc, offsetInt)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		includeDataStats := strings.ToLower(request.URL.Query().Get("include_data_stats")) == "true"
+		var results []model.Instance
+		ctx, token, err, errCode := getUserToken(control, request, true)
+		if err == nil && forAllUsers {
+			results, err, errCode = control.AdminListInstances(ctx, token, limitInt, offsetInt, orderBy, asc, filter, after, includeDataStats)
+		} else if err == nil {
+			results, err, errCode = control.ListInstances(ctx, token, limitInt, offsetInt, orderBy, asc, filter, after, includeDataStats)
+		}
 		if err != nil {
 			http.Error(writer, err.Error(), errCode)
 			return
 		}
+		next, err := nextCursor(config, orderBy, asc, limitInt, results)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if next != "" {
+			writer.Header().Set(nextCursorHeader, next)
+		}
 		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 		err = json.NewEncoder(writer).Encode(results)
 		if err != nil {
@@ -83,16 +169,20 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 	})
 
 	router.GET("/total"+resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		token, err := getToken(request)
+		forAllUsers := request.URL.Query().Get("for_user") == allUsers
+		filter, err := getInstanceFilter(request, forAllUsers)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		search := request.URL.Query().Get("search")
-		includeGenerated := strings.ToLower(request.URL.Query().Get("exclude_generated")) != "true"
-
-		count, err, errCode := control.CountInstances(token, search, includeGenerated)
+		var count int64
+		ctx, token, err, errCode := getUserToken(control, request, true)
+		if err == nil && forAllUsers {
+			count, err, errCode = control.AdminCountInstances(ctx, token, filter)
+		} else if err == nil {
+			count, err, errCode = control.CountInstances(ctx, token, filter)
+		}
 		if err != nil {
 			http.Error(writer, err.Error(), errCode)
 			return
@@ -102,13 +192,63 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 	})
 
 	router.GET(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		token, err := getToken(request)
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		id := params.ByName("id")
+		result, err, errCode := control.ReadInstance(ctx, id, token)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("ETag", etag(result.Version))
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+		return
+	})
+
+	router.GET(resource+"/:id/data-stats", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		id := params.ByName("id")
+		result, err, errCode := control.GetInstanceDataStats(ctx, id, token)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+		return
+	})
+
+	router.GET(resource+"/:id/preview", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		n := request.URL.Query().Get("n")
+		if n == "" {
+			n = "10"
+		}
+		nInt, err := strconv.ParseInt(n, 10, 64)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 		id := params.ByName("id")
-		result, err, errCode := control.ReadInstance(id, token)
+		result, err, errCode := control.PreviewInstance(ctx, id, token, nInt)
 		if err != nil {
 			http.Error(writer, err.Error(), errCode)
 			return
@@ -121,14 +261,48 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 		return
 	})
 
+	router.GET(resource+"/:id/logs", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		tail := request.URL.Query().Get("tail")
+		if tail == "" {
+			tail = "100"
+		}
+		tailInt, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id := params.ByName("id")
+		result, err, errCode := control.GetInstanceLogs(ctx, id, token, tailInt)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, err = writer.Write([]byte(result))
+		if err != nil {
+			log.Println("ERROR: unable to write response", err)
+		}
+		return
+	})
+
 	router.DELETE(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		token, err := getToken(request)
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		ifMatch, err := getIfMatch(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 		id := params.ByName("id")
-		err, errCode := control.DeleteInstance(id, token)
+		err, errCode := control.DeleteInstance(ctx, id, token, ifMatch)
 		if err != nil {
 			http.Error(writer, err.Error(), errCode)
 			return
@@ -138,9 +312,9 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 	})
 
 	router.PUT(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		token, err := getToken(request)
+		ctx, token, err, code := getUserToken(control, request, false)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusBadRequest)
+			http.Error(writer, err.Error(), code)
 			return
 		}
 		id := params.ByName("id")
@@ -155,7 +329,12 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 			http.Error(writer, "IDs don't match", http.StatusBadRequest)
 			return
 		}
-		err, code := control.SetInstance(instance, token)
+		ifMatch, err := getIfMatch(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err, code = control.SetInstance(ctx, instance, token, ifMatch)
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
@@ -163,6 +342,35 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 		writer.WriteHeader(http.StatusOK)
 	})
 
+	router.PATCH(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		ifMatch, err := getIfMatch(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		contentType, patch, err := getPatch(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := control.PatchInstance(ctx, params.ByName("id"), contentType, patch, token, ifMatch)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("ETag", etag(result.Version))
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
 	router.POST(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := getToken(request)
 		if err != nil {
@@ -175,7 +383,7 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		result, err, code := control.CreateInstance(instance, token)
+		result, err, code := control.CreateInstance(request.Context(), instance, token, request.Header.Get("Idempotency-Key"))
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
@@ -189,4 +397,220 @@ func InstancesEndpoints(_ config.Config, control Controller, router *httprouter.
 		}
 		return
 	})
+
+	router.POST(resource+"/:id/transfer", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		transfer := model.TransferRequest{}
+		err = json.NewDecoder(request.Body).Decode(&transfer)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err, code = control.TransferInstance(ctx, params.ByName("id"), transfer.NewOwner, token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	router.GET(resource+"/:id/permissions", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		result, err, code := control.GetInstancePermissions(ctx, params.ByName("id"), token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.PUT(resource+"/:id/permissions", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getUserToken(control, request, false)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		permissions := permV2Client.ResourcePermissions{}
+		err = json.NewDecoder(request.Body).Decode(&permissions)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := control.SetInstancePermissions(ctx, params.ByName("id"), permissions, token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+}
+
+// etag of an instance version
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// getIfMatch parses the If-Match header. A missing header or "*" does not restrict the version.
+func getIfMatch(request *http.Request) (*int64, error) {
+	value := strings.TrimSpace(request.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(value, "W/"), `"`), 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid If-Match header, expected the ETag of the instance")
+	}
+	return &version, nil
+}
+
+// getPatch returns the media type and body of a PATCH request
+func getPatch(request *http.Request) (contentType string, patch []byte, err error) {
+	contentType, _, err = mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		return contentType, patch, errors.New("invalid Content-Type header: " + err.Error())
+	}
+	patch, err = io.ReadAll(request.Body)
+	return contentType, patch, err
+}
+
+// nextCursorHeader carries the cursor of the next page, because the v1 list response is a plain array
+const nextCursorHeader = "X-Next-Cursor"
+
+// getCursor parses the cursor query parameter. A cursor replaces the offset.
+func getCursor(config config.Config, request *http.Request, sort string, asc bool, offset int64) (*model.InstanceCursor, error) {
+	value := request.URL.Query().Get("cursor")
+	if value == "" {
+		return nil, nil
+	}
+	if offset != 0 {
+		return nil, errors.New("cursor and offset can not be combined")
+	}
+	cursor, err := model.ParseInstanceCursor(value, cursorKey(config), sort, asc)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// nextCursor returns an empty string if results is the last page
+func nextCursor(config config.Config, sort string, asc bool, limit int64, results []model.Instance) (string, error) {
+	if limit <= 0 || int64(len(results)) < limit {
+		return "", nil
+	}
+	return model.NewInstanceCursor(sort, asc, results[len(results)-1]).Encrypt(cursorKey(config))
+}
+
+// randomCursorKey is used without CURSOR_SECRET; cursors are then only valid within this process
+var randomCursorKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+})
+
+func cursorKey(config config.Config) []byte {
+	if config.CursorSecret == "" {
+		return randomCursorKey()
+	}
+	return model.NewCursorKey(config.CursorSecret)
+}
+
+// getSort parses the sort query parameter (default name.asc). Sorting by owner is only supported for lists of all users.
+func getSort(request *http.Request, allUsers bool) (orderBy string, asc bool, err error) {
+	sort := request.URL.Query().Get("sort")
+	if sort == "" {
+		sort = "name"
+	}
+	orderBy = strings.Split(sort, ".")[0]
+	asc = !strings.HasSuffix(sort, ".desc")
+	if orderBy == "owner" && !allUsers {
+		return orderBy, asc, errors.New("sort by owner is only supported for lists of all users")
+	}
+	return orderBy, asc, nil
+}
+
+// getInstanceFilter parses the filter query parameters of instance lists; exclude_generated is supported for compatibility.
+// The owner filter is only supported for lists of all users.
+func getInstanceFilter(request *http.Request, allUsers bool) (filter model.InstanceFilter, err error) {
+	query := request.URL.Query()
+	filter = model.InstanceFilter{
+		Search:       query.Get("search"),
+		ImportTypeId: query.Get("import_type_id"),
+		Image:        query.Get("image"),
+	}
+	if allUsers {
+		filter.Owner = query.Get("owner")
+	} else if query.Get("owner") != "" {
+		return filter, errors.New("the owner filter is only supported for lists of all users")
+	}
+	filter.Restart, err = getOptionalBool(query.Get("restart"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Generated, err = getOptionalBool(query.Get("generated"))
+	if err != nil {
+		return filter, err
+	}
+	filter.CreatedAfter, err = getOptionalTime(query.Get("created_after"))
+	if err != nil {
+		return filter, err
+	}
+	filter.CreatedBefore, err = getOptionalTime(query.Get("created_before"))
+	if err != nil {
+		return filter, err
+	}
+	filter.UpdatedAfter, err = getOptionalTime(query.Get("updated_after"))
+	if err != nil {
+		return filter, err
+	}
+	filter.UpdatedBefore, err = getOptionalTime(query.Get("updated_before"))
+	if err != nil {
+		return filter, err
+	}
+	if filter.Generated == nil && strings.ToLower(query.Get("exclude_generated")) == "true" {
+		generated := false
+		filter.Generated = &generated
+	}
+	for _, label := range query["label"] {
+		key, value, found := strings.Cut(label, ":")
+		if !found || !model.ValidLabelKey(key) {
+			return filter, errors.New("label filters have to be formatted as key:value with a valid key")
+		}
+		if filter.Labels == nil {
+			filter.Labels = map[string]string{}
+		}
+		filter.Labels[key] = value
+	}
+	return filter, nil
+}
+
+func getOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	result, err := strconv.ParseBool(value)
+	return &result, err
+}
+
+func getOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	result, err := time.Parse(time.RFC3339, value)
+	return &result, err
 }
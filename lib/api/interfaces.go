@@ -17,15 +17,41 @@
 package api
 
 import (
+	"context"
+
 	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 )
 
 type Controller interface {
-	ListInstances(jwt jwt.Token, limit int64, offset int64, sort string, asc bool, search string, includeGenerated bool) (results []model.Instance, err error, errCode int)
-	ReadInstance(id string, jwt jwt.Token) (result model.Instance, err error, errCode int)
-	CreateInstance(instance model.Instance, jwt jwt.Token) (result model.Instance, err error, code int)
-	SetInstance(importType model.Instance, jwt jwt.Token) (err error, code int)
-	DeleteInstance(id string, jwt jwt.Token) (err error, errCode int)
-	CountInstances(jwt jwt.Token, search string, includeGenerated bool) (count int64, err error, errCode int)
+	CheckReadiness(ctx context.Context) (result model.Health, ready bool)
+	ImpersonateUser(ctx context.Context, token jwt.Token, userId string) (userCtx context.Context, result jwt.Token, err error, code int)
+
+	ListInstances(ctx context.Context, jwt jwt.Token, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor, includeDataStats bool) (results []model.Instance, err error, errCode int)
+	ReadInstance(ctx context.Context, id string, jwt jwt.Token) (result model.Instance, err error, errCode int)
+	CreateInstance(ctx context.Context, instance model.Instance, jwt jwt.Token, idempotencyKey string) (result model.Instance, err error, code int)
+	SetInstance(ctx context.Context, importType model.Instance, jwt jwt.Token, expectedVersion *int64) (err error, code int)
+	PatchInstance(ctx context.Context, id string, contentType string, patch []byte, jwt jwt.Token, expectedVersion *int64) (result model.Instance, err error, code int)
+	ExportInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter, ids []string, includeSecrets bool) (result model.InstanceBundle, err error, code int)
+	ImportInstances(ctx context.Context, jwt jwt.Token, bundle model.InstanceBundle, options model.ImportOptions) (result model.ImportReport, err error, code int)
+	ApplyManifest(ctx context.Context, jwt jwt.Token, manifest model.ApplyManifest, options model.ApplyOptions) (result model.ApplyResult, err error, code int)
+	DeleteInstance(ctx context.Context, id string, jwt jwt.Token, expectedVersion *int64) (err error, errCode int)
+	CountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (count int64, err error, errCode int)
+	GetInstanceDataStats(ctx context.Context, id string, jwt jwt.Token) (result model.DataStats, err error, errCode int)
+	PreviewInstance(ctx context.Context, id string, jwt jwt.Token, n int64) (result []model.PreviewRecord, err error, errCode int)
+	GetInstanceLogs(ctx context.Context, id string, jwt jwt.Token, tail int64) (logs string, err error, errCode int)
+	AdminListInstances(ctx context.Context, jwt jwt.Token, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor, includeDataStats bool) (results []model.Instance, err error, errCode int)
+	AdminCountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (count int64, err error, errCode int)
+	TransferInstance(ctx context.Context, id string, newOwner string, jwt jwt.Token) (err error, code int)
+	GetInstancePermissions(ctx context.Context, id string, jwt jwt.Token) (result permV2Client.ResourcePermissions, err error, code int)
+	SetInstancePermissions(ctx context.Context, id string, permissions permV2Client.ResourcePermissions, jwt jwt.Token) (result permV2Client.ResourcePermissions, err error, code int)
+	AdminTransferInstances(ctx context.Context, userId string, newOwner string, jwt jwt.Token) (result model.TransferResult, err error, code int)
+
+	ListWebhooks(ctx context.Context, jwt jwt.Token, limit int64, offset int64) (results []model.Webhook, err error, errCode int)
+	ReadWebhook(ctx context.Context, id string, jwt jwt.Token) (result model.Webhook, err error, errCode int)
+	CreateWebhook(ctx context.Context, webhook model.Webhook, jwt jwt.Token) (result model.Webhook, err error, code int)
+	SetWebhook(ctx context.Context, webhook model.Webhook, jwt jwt.Token) (err error, code int)
+	DeleteWebhook(ctx context.Context, id string, jwt jwt.Token) (err error, code int)
+	ListWebhookDeliveries(ctx context.Context, id string, jwt jwt.Token, limit int64, offset int64) (results []model.WebhookDelivery, err error, errCode int)
 }
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"embed"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+// operation documents a route in the OpenAPI document. Body and Response are example values, their types are reflected.
+type operation struct {
+	Summary             string
+	Description         string
+	Query               []parameter
+	Body                any
+	Response            any
+	ResponseContentType string // defaults to application/json
+	Status              int    // success status, defaults to 200
+	Public              bool   // no Authorization header needed
+	JsonErrors          bool   // errors are model.ErrorResponse instead of plain text
+}
+
+type parameter struct {
+	Name        string
+	Description string
+	Type        string // string, integer or boolean
+}
+
+type route struct {
+	Method string
+	Path   string
+}
+
+var operations = map[route]operation{}
+
+// document registers the documentation of a route. Every route registered by the endpoints needs to be documented.
+func document(method string, path string, op operation) {
+	operations[route{Method: method, Path: path}] = op
+}
+
+var limitParameter = parameter{Name: "limit", Description: "max number of results", Type: "integer"}
+var offsetParameter = parameter{Name: "offset", Description: "number of results to skip", Type: "integer"}
+var cursorParameter = parameter{Name: "cursor", Description: "next_cursor of the previous page, replaces offset; sort has to stay the same", Type: "string"}
+var sortParameter = parameter{Name: "sort", Description: "field to sort by, append .desc for descending order; owner only for lists of all users", Type: "string"}
+var ownerParameter = parameter{Name: "owner", Description: "filter by owner user id; only for lists of all users", Type: "string"}
+
+const patchDescription = "the body is a JSON Merge Patch (Content-Type application/merge-patch+json) or JSON Patch (application/json-patch+json) of {name, configs: {config name: value}, restart, labels}; removed configs are reset to their default"
+const idempotencyKeyDescription = "retries with the same Idempotency-Key header return the response of the first request; reusing the key for a different request is rejected with 422"
+const ifMatchDescription = "an optional If-Match header with the ETag of the read instance rejects the request with 412, if the instance was modified since"
+
+// instanceFilterParameters are parsed by getInstanceFilter
+var instanceFilterParameters = []parameter{
+	{Name: "search", Description: "filter by name", Type: "string"},
+	{Name: "import_type_id", Description: "filter by import type", Type: "string"},
+	{Name: "image", Description: "filter by image", Type: "string"},
+	{Name: "restart", Description: "filter by restart mode", Type: "boolean"},
+	{Name: "generated", Description: "filter by generated flag", Type: "boolean"},
+	{Name: "exclude_generated", Description: "deprecated, use generated=false", Type: "boolean"},
+	{Name: "created_after", Description: "RFC3339 time, inclusive", Type: "string"},
+	{Name: "created_before", Description: "RFC3339 time, exclusive", Type: "string"},
+	{Name: "updated_after", Description: "RFC3339 time, inclusive", Type: "string"},
+	{Name: "updated_before", Description: "RFC3339 time, exclusive", Type: "string"},
+	{Name: "label", Description: "key:value, may be repeated; all labels have to match", Type: "string"},
+}
+
+// buildOpenApi returns an OpenAPI 3 document of the documented routes. openapi_test.go ensures that every route is documented.
+func buildOpenApi(routes []route) (result []byte, err error) {
+	schemas := schemaRegistry{}
+	paths := map[string]map[string]any{}
+	for _, r := range routes {
+		op, ok := operations[r]
+		if !ok {
+			continue
+		}
+		path, pathParams := openApiPath(r.Path)
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(r.Method)] = schemas.operation(r, op, pathParams)
+	}
+	return json.Marshal(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "import-deploy",
+			"version": "1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []any{map[string]any{"bearerAuth": []string{}}},
+	})
+}
+
+// openApiPath converts httprouter parameters (/:id) to OpenAPI parameters (/{id})
+func openApiPath(path string) (result string, params []string) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+type schemaRegistry map[string]any
+
+func (this schemaRegistry) operation(r route, op operation, pathParams []string) map[string]any {
+	params := []any{}
+	for _, name := range pathParams {
+		params = append(params, map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+	}
+	for _, p := range op.Query {
+		params = append(params, map[string]any{"name": p.Name, "in": "query", "description": p.Description, "schema": map[string]any{"type": p.Type}})
+	}
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]any{"description": http.StatusText(status)}
+	if op.Response != nil {
+		contentType := op.ResponseContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success["content"] = map[string]any{contentType: map[string]any{"schema": this.schema(reflect.TypeOf(op.Response))}}
+	}
+	failure := map[string]any{"description": "error message", "content": map[string]any{"text/plain": map[string]any{"schema": map[string]any{"type": "string"}}}}
+	if op.JsonErrors {
+		failure["content"] = map[string]any{"application/json": map[string]any{"schema": this.schema(reflect.TypeOf(model.ErrorResponse{}))}}
+	}
+	result := map[string]any{
+		"summary":    op.Summary,
+		"tags":       []string{strings.Split(strings.TrimPrefix(r.Path, "/"), "/")[0]},
+		"parameters": params,
+		"responses": map[string]any{
+			strconv.Itoa(status): success,
+			"default":            failure,
+		},
+	}
+	if op.Description != "" {
+		result["description"] = op.Description
+	}
+	if op.Body != nil {
+		result["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": this.schema(reflect.TypeOf(op.Body))}},
+		}
+	}
+	if op.Public {
+		result["security"] = []any{}
+	}
+	return result
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// schema returns the schema of t. Named structs are added to the registry and referenced.
+func (this schemaRegistry) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		result := this.schema(t.Elem())
+		if _, isRef := result["$ref"]; isRef {
+			return map[string]any{"allOf": []any{result}, "nullable": true}
+		}
+		result["nullable"] = true
+		return result
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": this.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": this.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return this.structSchema(t)
+		}
+		if _, ok := this[t.Name()]; !ok {
+			this[t.Name()] = map[string]any{} // placeholder for recursive types
+			this[t.Name()] = this.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (this schemaRegistry) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" || (field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct) {
+			continue // embedded structs are flattened by VisibleFields
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = this.schema(field.Type)
+		if !slices.Contains(tag[1:], "omitempty") {
+			required = append(required, name)
+		}
+	}
+	result := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		slices.Sort(required)
+		result["required"] = required
+	}
+	return result
+}
+
+// swaggerUiAssets contains swagger-ui-dist 4.15.5 (Apache License 2.0), served at /docs/:file
+//
+//go:embed swagger-ui/swagger-ui.css swagger-ui/swagger-ui-bundle.js
+var swaggerUiAssets embed.FS
+
+// swaggerUi renders /openapi.json with the embedded assets
+const swaggerUi = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8"/>
+    <title>import-deploy</title>
+    <link rel="stylesheet" href="docs/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="docs/swagger-ui-bundle.js"></script>
+<script>
+    window.onload = () => {
+        window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+    };
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package api
+
+import (
+	"encoding/json"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/julienschmidt/httprouter"
+)
+
+// TestOpenApiDocumentsAllRoutes compares the registered routes with the documented ones
+func TestOpenApiDocumentsAllRoutes(t *testing.T) {
+	router := &instrumentedRouter{router: httprouter.New(), metrics: metrics.New()}
+	for _, e := range endpoints {
+		e(config.Config{}, nil, router)
+	}
+	registered := map[route]bool{}
+	for _, r := range router.routes {
+		registered[r] = true
+		if _, ok := operations[r]; !ok {
+			t.Error("missing openapi documentation of", r.Method, r.Path)
+		}
+	}
+	for r := range operations {
+		if !registered[r] {
+			t.Error("openapi documentation of unknown route", r.Method, r.Path)
+		}
+	}
+
+	spec, err := buildOpenApi(router.routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}{}
+	err = json.Unmarshal(spec, &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range router.routes {
+		path, _ := openApiPath(r.Path)
+		if _, ok := doc.Paths[path][strings.ToLower(r.Method)]; !ok {
+			t.Error("route missing in openapi document", r.Method, r.Path)
+		}
+	}
+}
+
+func TestSwaggerUiAssets(t *testing.T) {
+	for _, name := range []string{"swagger-ui/swagger-ui.css", "swagger-ui/swagger-ui-bundle.js"} {
+		content, err := fs.ReadFile(swaggerUiAssets, name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if len(content) == 0 {
+			t.Error("empty asset", name)
+		}
+		if !strings.Contains(swaggerUi, "docs/"+strings.TrimPrefix(name, "swagger-ui/")) {
+			t.Error("asset not referenced", name)
+		}
+	}
+}
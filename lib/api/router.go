@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/julienschmidt/httprouter"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Router is the subset of *httprouter.Router used to register endpoints
+type Router interface {
+	Handle(method string, path string, handle httprouter.Handle)
+	GET(path string, handle httprouter.Handle)
+	POST(path string, handle httprouter.Handle)
+	PUT(path string, handle httprouter.Handle)
+	PATCH(path string, handle httprouter.Handle)
+	DELETE(path string, handle httprouter.Handle)
+}
+
+// instrumentedRouter wraps every registered handle with request metrics and tracing
+// and records the routes for the openapi documentation
+type instrumentedRouter struct {
+	router  *httprouter.Router
+	metrics *metrics.Metrics
+	routes  []route
+}
+
+func (this *instrumentedRouter) Handle(method string, path string, handle httprouter.Handle) {
+	this.routes = append(this.routes, route{Method: method, Path: path})
+	this.router.Handle(method, path, this.metrics.InstrumentHandle(method, path, nameSpan(method, path, handle)))
+}
+
+// nameSpan names the request span, started by otelhttp, after the route pattern
+func nameSpan(method string, path string, handle httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		span := trace.SpanFromContext(request.Context())
+		span.SetName(method + " " + path)
+		span.SetAttributes(semconv.HTTPRoute(path))
+		handle(writer, request, params)
+	}
+}
+
+func (this *instrumentedRouter) GET(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodGet, path, handle)
+}
+
+func (this *instrumentedRouter) POST(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPost, path, handle)
+}
+
+func (this *instrumentedRouter) PUT(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPut, path, handle)
+}
+
+func (this *instrumentedRouter) PATCH(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPatch, path, handle)
+}
+
+func (this *instrumentedRouter) DELETE(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodDelete, path, handle)
+}
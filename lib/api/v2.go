@@ -0,0 +1,256 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, V2Endpoints)
+
+	forUserParameter := parameter{Name: "for_user", Description: "admins only: act on behalf of this user id", Type: "string"}
+	document(http.MethodGet, "/v2/instances", operation{
+		Summary:     "list instances with their total count",
+		Description: "next_cursor is set if more instances may follow",
+		Query: slices.Concat([]parameter{limitParameter, offsetParameter, cursorParameter, sortParameter}, instanceFilterParameters, []parameter{
+			{Name: "include_data_stats", Description: "add data statistics of the kafka topics", Type: "boolean"},
+			{Name: "for_user", Description: "admins only: act on behalf of this user id, * for all users", Type: "string"},
+			ownerParameter,
+		}),
+		Response:   model.InstanceList{},
+		JsonErrors: true,
+	})
+	document(http.MethodGet, "/v2/instances/:id", operation{Summary: "read instance", Description: "the ETag header contains the version", Query: []parameter{forUserParameter}, Response: model.Instance{}, JsonErrors: true})
+	document(http.MethodPost, "/v2/instances", operation{Summary: "create instance", Description: idempotencyKeyDescription, Body: model.Instance{}, Response: model.Instance{}, Status: http.StatusCreated, JsonErrors: true})
+	document(http.MethodPut, "/v2/instances/:id", operation{Summary: "update instance", Description: ifMatchDescription, Query: []parameter{forUserParameter}, Body: model.Instance{}, Response: model.Instance{}, JsonErrors: true})
+	document(http.MethodPatch, "/v2/instances/:id", operation{
+		Summary:     "update name, configs, restart and labels of the instance",
+		Description: patchDescription + "; " + ifMatchDescription,
+		Query:       []parameter{forUserParameter},
+		Body:        map[string]any{},
+		Response:    model.Instance{},
+		JsonErrors:  true,
+	})
+	document(http.MethodDelete, "/v2/instances/:id", operation{Summary: "delete instance", Description: ifMatchDescription, Query: []parameter{forUserParameter}, Status: http.StatusNoContent, JsonErrors: true})
+}
+
+// V2Endpoints return lists with their total count and errors as model.ErrorResponse. The v1 routes are unchanged.
+func V2Endpoints(config config.Config, control Controller, router Router) {
+	resource := "/v2/instances"
+
+	router.GET(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		limit, offset, err := getLimitOffset(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		forAllUsers := request.URL.Query().Get("for_user") == allUsers
+		orderBy, asc, err := getSort(request, forAllUsers)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		after, err := getCursor(config, request, orderBy, asc, offset)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		filter, err := getInstanceFilter(request, forAllUsers)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		includeDataStats := strings.ToLower(request.URL.Query().Get("include_data_stats")) == "true"
+
+		result := model.InstanceList{}
+		ctx, token, err, code := getV2UserToken(control, request, true)
+		if err == nil && forAllUsers {
+			result.Items, err, code = control.AdminListInstances(ctx, token, limit, offset, orderBy, asc, filter, after, includeDataStats)
+			if err == nil {
+				result.Total, err, code = control.AdminCountInstances(ctx, token, filter)
+			}
+		} else if err == nil {
+			result.Items, err, code = control.ListInstances(ctx, token, limit, offset, orderBy, asc, filter, after, includeDataStats)
+			if err == nil {
+				result.Total, err, code = control.CountInstances(ctx, token, filter)
+			}
+		}
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		if result.Items == nil {
+			result.Items = []model.Instance{}
+		}
+		result.NextCursor, err = nextCursor(config, orderBy, asc, limit, result.Items)
+		if err != nil {
+			writeJsonError(writer, http.StatusInternalServerError, err)
+			return
+		}
+		writeJson(writer, http.StatusOK, result)
+	})
+
+	router.GET(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getV2UserToken(control, request, false)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		result, err, code := control.ReadInstance(ctx, params.ByName("id"), token)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		writer.Header().Set("ETag", etag(result.Version))
+		writeJson(writer, http.StatusOK, result)
+	})
+
+	router.POST(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusUnauthorized, err)
+			return
+		}
+		instance := model.Instance{}
+		err = json.NewDecoder(request.Body).Decode(&instance)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		result, err, code := control.CreateInstance(request.Context(), instance, token, request.Header.Get("Idempotency-Key"))
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		writer.Header().Set("Location", resource+"/"+result.Id)
+		writer.Header().Set("ETag", etag(result.Version))
+		writeJson(writer, http.StatusCreated, result)
+	})
+
+	router.PUT(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getV2UserToken(control, request, false)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		instance := model.Instance{}
+		err = json.NewDecoder(request.Body).Decode(&instance)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		if instance.Id != params.ByName("id") {
+			writeJsonError(writer, http.StatusBadRequest, errors.New("IDs don't match"))
+			return
+		}
+		ifMatch, err := getIfMatch(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		err, code = control.SetInstance(ctx, instance, token, ifMatch)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		result, err, code := control.ReadInstance(ctx, instance.Id, token)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		writer.Header().Set("ETag", etag(result.Version))
+		writeJson(writer, http.StatusOK, result)
+	})
+
+	router.PATCH(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getV2UserToken(control, request, false)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		ifMatch, err := getIfMatch(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		contentType, patch, err := getPatch(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		result, err, code := control.PatchInstance(ctx, params.ByName("id"), contentType, patch, token, ifMatch)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		writer.Header().Set("ETag", etag(result.Version))
+		writeJson(writer, http.StatusOK, result)
+	})
+
+	router.DELETE(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		ctx, token, err, code := getV2UserToken(control, request, false)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		ifMatch, err := getIfMatch(request)
+		if err != nil {
+			writeJsonError(writer, http.StatusBadRequest, err)
+			return
+		}
+		err, code = control.DeleteInstance(ctx, params.ByName("id"), token, ifMatch)
+		if err != nil {
+			writeJsonError(writer, code, err)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// getV2UserToken is getUserToken, but responds to missing or invalid tokens with 401
+func getV2UserToken(control Controller, request *http.Request, allowAllUsers bool) (ctx context.Context, token jwt.Token, err error, code int) {
+	_, err = getToken(request)
+	if err != nil {
+		return request.Context(), token, err, http.StatusUnauthorized
+	}
+	return getUserToken(control, request, allowAllUsers)
+}
+
+func writeJson(writer http.ResponseWriter, code int, value any) {
+	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	writer.WriteHeader(code)
+	err := json.NewEncoder(writer).Encode(value)
+	if err != nil {
+		log.Println("ERROR: unable to encode response", err)
+	}
+}
+
+func writeJsonError(writer http.ResponseWriter, code int, err error) {
+	writeJson(writer, code, model.ErrorResponse{Status: code, Error: err.Error()})
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, WebhooksEndpoints)
+
+	document(http.MethodGet, "/webhooks", operation{Summary: "list own webhooks", Query: []parameter{limitParameter, offsetParameter}, Response: []model.Webhook{}})
+	document(http.MethodGet, "/webhooks/:id", operation{Summary: "read webhook", Response: model.Webhook{}})
+	document(http.MethodGet, "/webhooks/:id/deliveries", operation{Summary: "list deliveries of the webhook", Query: []parameter{limitParameter, offsetParameter}, Response: []model.WebhookDelivery{}})
+	document(http.MethodPost, "/webhooks", operation{Summary: "create webhook, the secret is only returned once", Body: model.Webhook{}, Response: model.Webhook{}})
+	document(http.MethodPut, "/webhooks/:id", operation{Summary: "update webhook", Body: model.Webhook{}})
+	document(http.MethodDelete, "/webhooks/:id", operation{Summary: "delete webhook", Status: http.StatusNoContent})
+}
+
+func WebhooksEndpoints(_ config.Config, control Controller, router Router) {
+	resource := "/webhooks"
+
+	router.GET(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit, offset, err := getLimitOffset(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		results, err, errCode := control.ListWebhooks(request.Context(), token, limit, offset)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(results)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.GET(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, errCode := control.ReadWebhook(request.Context(), params.ByName("id"), token)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.GET(resource+"/:id/deliveries", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit, offset, err := getLimitOffset(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		results, err, errCode := control.ListWebhookDeliveries(request.Context(), params.ByName("id"), token, limit, offset)
+		if err != nil {
+			http.Error(writer, err.Error(), errCode)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(results)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.POST(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		webhook := model.Webhook{}
+		err = json.NewDecoder(request.Body).Decode(&webhook)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := control.CreateWebhook(request.Context(), webhook, token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		writer.WriteHeader(code)
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			log.Println("ERROR: unable to encode response", err)
+		}
+	})
+
+	router.PUT(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		webhook := model.Webhook{}
+		err = json.NewDecoder(request.Body).Decode(&webhook)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if params.ByName("id") != webhook.Id {
+			http.Error(writer, "IDs don't match", http.StatusBadRequest)
+			return
+		}
+		err, code := control.SetWebhook(request.Context(), webhook, token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	router.DELETE(resource+"/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := getToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err, code := control.DeleteWebhook(request.Context(), params.ByName("id"), token)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(code)
+	})
+}
+
+func getLimitOffset(request *http.Request) (limit int64, offset int64, err error) {
+	limitStr := request.URL.Query().Get("limit")
+	if limitStr == "" {
+		limitStr = "100"
+	}
+	limit, err = strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		return limit, offset, err
+	}
+	offsetStr := request.URL.Query().Get("offset")
+	if offsetStr == "" {
+		offsetStr = "0"
+	}
+	offset, err = strconv.ParseInt(offsetStr, 10, 64)
+	return limit, offset, err
+}
@@ -62,6 +62,19 @@ func do[T any](req *http.Request) (result T, err error, code int) {
 	return
 }
 
+func doWithoutResult(req *http.Request) (err error, code int) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	defer resp.Body.Close()
+	temp, _ := io.ReadAll(resp.Body) //read error response end ensure that resp.Body is read to EOF
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected statuscode %v: %v", resp.StatusCode, string(temp)), resp.StatusCode
+	}
+	return nil, resp.StatusCode
+}
+
 func prefixTokenIfNeeded(jwt jwt.Token) string {
 	s := jwt.Jwt()
 	if !strings.HasPrefix(strings.ToLower(s), "bearer ") {
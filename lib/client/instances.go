@@ -19,10 +19,12 @@ package client
 import (
 	"bytes"
 	"encoding/json"
-	"github.com/SENERGY-Platform/import-deploy/lib/model"
-	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"net/http"
+	"net/url"
 	"strconv"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 )
 
 func (c *Client) ListInstances(jwt jwt.Token, limit int64, offset int64, sort string, asc bool, search string, includeGenerated bool, forUser string) (results []model.Instance, err error, errCode int) {
@@ -32,14 +34,16 @@ func (c *Client) ListInstances(jwt jwt.Token, limit int64, offset int64, sort st
 		sort += ".desc"
 	}
 
-	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/instances"+
-		"?limit="+strconv.FormatInt(limit, 10)+
-		"&offset="+strconv.FormatInt(offset, 10)+
-		"&sort="+sort+
-		"&search="+search+
-		"&exclude_generated="+strconv.FormatBool(!includeGenerated)+
-		"&for_user="+forUser,
-		nil)
+	query := url.Values{}
+	query.Set("limit", strconv.FormatInt(limit, 10))
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("sort", sort)
+	query.Set("search", search)
+	query.Set("exclude_generated", strconv.FormatBool(!includeGenerated))
+	if forUser != "" {
+		query.Set("for_user", forUser)
+	}
+	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/instances?"+query.Encode(), nil)
 	if err != nil {
 		return results, err, http.StatusBadRequest
 	}
@@ -48,7 +52,7 @@ func (c *Client) ListInstances(jwt jwt.Token, limit int64, offset int64, sort st
 }
 
 func (c *Client) ReadInstance(id string, jwt jwt.Token, forUser string) (result model.Instance, err error, errCode int) {
-	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/instances/"+id+"&for_user="+forUser, nil)
+	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/instances/"+url.PathEscape(id)+forUserQuery(forUser), nil)
 	if err != nil {
 		return result, err, http.StatusBadRequest
 	}
@@ -74,39 +78,38 @@ func (c *Client) SetInstance(importType model.Instance, jwt jwt.Token) (err erro
 	if err != nil {
 		return err, http.StatusBadRequest
 	}
-	req, err := http.NewRequest(http.MethodPost, c.baseUrl+"/instances", bytes.NewBuffer(b))
+	req, err := http.NewRequest(http.MethodPut, c.baseUrl+"/instances/"+url.PathEscape(importType.Id), bytes.NewBuffer(b))
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
 	req.Header.Set("Authorization", prefixTokenIfNeeded(jwt))
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err, http.StatusInternalServerError
-	}
-	return nil, resp.StatusCode
+	return doWithoutResult(req)
 }
 
 func (c *Client) DeleteInstance(id string, jwt jwt.Token, forUser string) (err error, errCode int) {
-	req, err := http.NewRequest(http.MethodDelete, c.baseUrl+"/instances/"+id+"&for_user="+forUser, nil)
+	req, err := http.NewRequest(http.MethodDelete, c.baseUrl+"/instances/"+url.PathEscape(id)+forUserQuery(forUser), nil)
 	if err != nil {
 		return err, http.StatusBadRequest
 	}
 	req.Header.Set("Authorization", prefixTokenIfNeeded(jwt))
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err, http.StatusInternalServerError
-	}
-	return nil, resp.StatusCode
+	return doWithoutResult(req)
 }
 
 func (c *Client) CountInstances(jwt jwt.Token, search string, includeGenerated bool) (count int64, err error, errCode int) {
-	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/instances"+
-		"?search="+search+
-		"&exclude_generated="+strconv.FormatBool(!includeGenerated),
-		nil)
+	query := url.Values{}
+	query.Set("search", search)
+	query.Set("exclude_generated", strconv.FormatBool(!includeGenerated))
+	req, err := http.NewRequest(http.MethodGet, c.baseUrl+"/total/instances?"+query.Encode(), nil)
 	if err != nil {
 		return 0, err, http.StatusBadRequest
 	}
 	req.Header.Set("Authorization", prefixTokenIfNeeded(jwt))
 	return do[int64](req)
 }
+
+func forUserQuery(forUser string) string {
+	if forUser == "" {
+		return ""
+	}
+	return "?for_user=" + url.QueryEscape(forUser)
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// AdminListInstances lists the instances of all users
+func (this *Client) AdminListInstances(ctx context.Context, token jwt.Token, options ListOptions) (result model.AdminInstanceList, err error) {
+	query := options.query()
+	if options.IncludeDataStats {
+		query.Set("include_data_stats", "true")
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"admin", "instances"}, query: query}, &result)
+	return result, err
+}
+
+// AdminTransferInstances changes the owner of all instances of the user
+func (this *Client) AdminTransferInstances(ctx context.Context, token jwt.Token, userId string, newOwner string) (result model.TransferResult, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"admin", "users", userId, "transfer"}, body: model.TransferRequest{NewOwner: newOwner}}, &result)
+	return result, err
+}
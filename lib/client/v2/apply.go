@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// Apply creates, updates and, with options.Prune, deletes instances of the user to match the manifest
+func (this *Client) Apply(ctx context.Context, token jwt.Token, manifest model.ApplyManifest, options model.ApplyOptions) (result model.ApplyResult, err error) {
+	query := url.Values{}
+	if options.DryRun {
+		query.Set("dry_run", strconv.FormatBool(options.DryRun))
+	}
+	if options.Prune {
+		query.Set("prune", strconv.FormatBool(options.Prune))
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"apply"}, query: query, body: manifest}, &result)
+	return result, err
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// ExportInstances returns the definitions of the instances matching the filter, restricted to ids if not empty
+func (this *Client) ExportInstances(ctx context.Context, token jwt.Token, filter model.InstanceFilter, ids []string, includeSecrets bool) (result model.InstanceBundle, err error) {
+	query := filterQuery(filter)
+	for _, id := range ids {
+		query.Add("id", id)
+	}
+	if includeSecrets {
+		query.Set("include_secrets", "true")
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"export", "instances"}, query: query}, &result)
+	return result, err
+}
+
+// ImportInstances creates or updates the instances of the bundle, see model.ImportOptions
+func (this *Client) ImportInstances(ctx context.Context, token jwt.Token, bundle model.InstanceBundle, options model.ImportOptions) (result model.ImportReport, err error) {
+	query := url.Values{}
+	if options.DryRun {
+		query.Set("dry_run", strconv.FormatBool(options.DryRun))
+	}
+	if options.OnConflict != "" {
+		query.Set("on_conflict", string(options.OnConflict))
+	}
+	for from, to := range options.ImportTypeMapping {
+		query.Add("import_type_map", from+":"+to)
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"import", "instances"}, query: query, body: bundle}, &result)
+	return result, err
+}
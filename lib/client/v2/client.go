@@ -0,0 +1,230 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package client is a context aware client of the import-deploy api.
+// Idempotent requests are retried on network errors and on 502, 503 and 504 responses.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	retries    int
+	retryWait  time.Duration
+	forUser    string
+}
+
+type Option func(client *Client)
+
+// WithHttpClient replaces the default http client, which uses a timeout of 30s
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the number of retries of idempotent requests and the wait time before the first retry,
+// which is doubled for every further retry. Defaults to 3 retries and 500ms.
+func WithRetries(retries int, wait time.Duration) Option {
+	return func(client *Client) {
+		client.retries = retries
+		client.retryWait = wait
+	}
+}
+
+func New(baseUrl string, options ...Option) *Client {
+	client := &Client{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retries:    3,
+		retryWait:  500 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// ForUser returns a copy of the client, which lets admins act on behalf of the user.
+// "*" lists and counts the instances of all users.
+func (this *Client) ForUser(userId string) *Client {
+	result := *this
+	result.forUser = userId
+	return &result
+}
+
+// Error is returned for responses with a status code other than 2xx
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (this *Error) Error() string {
+	return http.StatusText(this.StatusCode) + ": " + this.Message
+}
+
+// StatusCode returns the status code of an *Error, 0 for other errors
+func StatusCode(err error) int {
+	var clientErr *Error
+	if errors.As(err, &clientErr) {
+		return clientErr.StatusCode
+	}
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsPreconditionFailed is true if the instance was modified since the version passed with If-Match
+func IsPreconditionFailed(err error) bool {
+	return StatusCode(err) == http.StatusPreconditionFailed
+}
+
+// request is used to build requests; it is sent with doJson or do
+type request struct {
+	method  string
+	path    []string // segments are escaped
+	query   url.Values
+	body    any
+	headers http.Header
+}
+
+func (this *Client) url(r request) string {
+	u := this.baseUrl
+	for _, segment := range r.path {
+		u += "/" + url.PathEscape(segment)
+	}
+	query := url.Values{}
+	for key, values := range r.query {
+		query[key] = values
+	}
+	if this.forUser != "" {
+		query.Set("for_user", this.forUser)
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// doJson sends the request and decodes the json response body into result, if result is not nil
+func (this *Client) doJson(ctx context.Context, token jwt.Token, r request, result any) (header http.Header, err error) {
+	header, body, err := this.do(ctx, token, r)
+	if err != nil {
+		return header, err
+	}
+	if result != nil {
+		err = json.Unmarshal(body, result)
+	}
+	return header, err
+}
+
+func (this *Client) do(ctx context.Context, token jwt.Token, r request) (header http.Header, body []byte, err error) {
+	var payload []byte
+	if r.body != nil {
+		payload, err = json.Marshal(r.body)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	retries := 0
+	if isIdempotent(r) {
+		retries = this.retries
+	}
+	wait := this.retryWait
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		header, body, retry, err = this.send(ctx, token, r, payload)
+		if !retry || attempt >= retries {
+			return header, body, err
+		}
+		select {
+		case <-ctx.Done():
+			return header, body, err
+		case <-time.After(wait):
+		}
+		wait = wait * 2
+	}
+}
+
+func (this *Client) send(ctx context.Context, token jwt.Token, r request, payload []byte) (header http.Header, body []byte, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, r.method, this.url(r), bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range r.headers {
+		req.Header[key] = values
+	}
+	if token.Token != "" {
+		req.Header.Set("Authorization", prefixTokenIfNeeded(token))
+	}
+	resp, err := this.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.Header, nil, ctx.Err() == nil, err
+	}
+	if resp.StatusCode > 299 {
+		message := strings.TrimSpace(string(body))
+		errResponse := model.ErrorResponse{}
+		if json.Unmarshal(body, &errResponse) == nil && errResponse.Error != "" {
+			message = errResponse.Error // /v2 routes
+		}
+		err = &Error{StatusCode: resp.StatusCode, Message: message}
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return resp.Header, body, true, err
+		}
+		return resp.Header, body, false, err
+	}
+	return resp.Header, body, false, nil
+}
+
+func isIdempotent(r request) bool {
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.headers.Get("Idempotency-Key") != ""
+}
+
+func prefixTokenIfNeeded(token jwt.Token) string {
+	s := token.Jwt()
+	if !strings.HasPrefix(strings.ToLower(s), "bearer ") {
+		s = "Bearer " + s
+	}
+	return s
+}
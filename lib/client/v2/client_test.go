@@ -0,0 +1,253 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+func TestInstanceLifecycle(t *testing.T) {
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL)
+	ctx := context.Background()
+	token := testToken("user1")
+
+	created, err := c.CreateInstance(ctx, token, model.Instance{Name: "a b%c?d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Id == "" || created.Name != "a b%c?d" {
+		t.Fatal(created)
+	}
+	read, err := c.ReadInstance(ctx, token, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Id != created.Id {
+		t.Error(read)
+	}
+	read.Name = "updated"
+	stored, err := c.SetInstanceIfUnchanged(ctx, token, read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "updated" || stored.Version != read.Version+1 {
+		t.Error(stored)
+	}
+	_, err = c.SetInstanceIfUnchanged(ctx, token, read)
+	if !IsPreconditionFailed(err) {
+		t.Error(err)
+	}
+	list, err := c.ListInstances(ctx, token, ListOptions{Filter: model.InstanceFilter{Search: "a&b=c #d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 1 || len(list.Items) != 1 {
+		t.Error(list)
+	}
+	count, err := c.CountInstances(ctx, token, ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error(count)
+	}
+	err = c.DeleteInstance(ctx, token, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.ReadInstance(ctx, token, created.Id)
+	if !IsNotFound(err) {
+		t.Error(err)
+	}
+
+	calls := control.recorded()
+	for _, c := range calls {
+		if c.User != "user1" {
+			t.Error("unexpected user", c)
+		}
+		if c.Method == "ListInstances" && c.Filter.Search != "a&b=c #d" {
+			t.Error("search not escaped", c.Filter.Search)
+		}
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL)
+	id := "a b%c?d#e"
+	_, err := c.ReadInstance(context.Background(), testToken("user1"), id)
+	if !IsNotFound(err) {
+		t.Fatal(err)
+	}
+	calls := control.recorded()
+	if len(calls) != 1 || calls[0].Id != id {
+		t.Error(calls)
+	}
+}
+
+func TestForUser(t *testing.T) {
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL)
+	ctx := context.Background()
+	admin := testToken("admin1", "admin")
+
+	created, err := c.CreateInstance(ctx, testToken("user1"), model.Instance{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := c.ForUser("user1").ReadInstance(ctx, admin, created.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Id != created.Id {
+		t.Error(read)
+	}
+	logs, err := c.ForUser("user1").GetInstanceLogs(ctx, admin, created.Id, 0)
+	if err != nil || logs != "logs of "+created.Id {
+		t.Error(logs, err)
+	}
+	_, err = c.ReadInstance(ctx, admin, created.Id)
+	if !IsNotFound(err) {
+		t.Error(err)
+	}
+	_, err = c.ForUser("user1").ReadInstance(ctx, testToken("user2"), created.Id)
+	if StatusCode(err) != http.StatusForbidden {
+		t.Error(err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL, WithRetries(0, time.Millisecond))
+	ctx := context.Background()
+	token := testToken("user1")
+
+	// v2 routes respond with model.ErrorResponse
+	_, err := c.ReadInstance(ctx, token, "unknown")
+	var clientErr *Error
+	if !errors.As(err, &clientErr) {
+		t.Fatal(err)
+	}
+	if clientErr.StatusCode != http.StatusNotFound || clientErr.Message != "not found" {
+		t.Error(clientErr)
+	}
+
+	// v1 routes respond with text
+	err = c.TransferInstance(ctx, token, "unknown", "user2")
+	if !errors.As(err, &clientErr) {
+		t.Fatal(err)
+	}
+	if clientErr.StatusCode != http.StatusNotFound || clientErr.Message != "not found" {
+		t.Error(clientErr)
+	}
+
+	_, err = c.ReadInstance(ctx, jwtWithoutToken(), "unknown")
+	if StatusCode(err) != http.StatusUnauthorized {
+		t.Error(err)
+	}
+	if StatusCode(errors.New("other")) != 0 {
+		t.Error("expected 0 for other errors")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+	token := testToken("user1")
+	for _, code := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		control := newFakeController()
+		server := newTestServer(t, control)
+		c := New(server.URL, WithRetries(3, time.Millisecond))
+
+		control.fail(code, code)
+		_, err := c.ListInstances(ctx, token, ListOptions{})
+		if err != nil {
+			t.Error(code, err)
+		}
+		if calls := control.count("ListInstances"); calls != 3 {
+			t.Error(code, "expected 2 retries", calls)
+		}
+
+		control.fail(code, code, code, code)
+		_, err = c.ReadInstance(ctx, token, "id")
+		if StatusCode(err) != code {
+			t.Error(code, err)
+		}
+		if calls := control.count("ReadInstance"); calls != 4 {
+			t.Error(code, "expected 3 retries", calls)
+		}
+	}
+
+	// other errors are not retried
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL, WithRetries(3, time.Millisecond))
+	control.fail(http.StatusInternalServerError)
+	_, err := c.ListInstances(ctx, token, ListOptions{})
+	if StatusCode(err) != http.StatusInternalServerError {
+		t.Error(err)
+	}
+	if calls := control.count("ListInstances"); calls != 1 {
+		t.Error("unexpected retry", calls)
+	}
+}
+
+func TestRetryOfPost(t *testing.T) {
+	ctx := context.Background()
+	token := testToken("user1")
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := New(server.URL, WithRetries(3, time.Millisecond))
+
+	// POST without Idempotency-Key
+	control.fail(http.StatusServiceUnavailable)
+	err := c.TransferInstance(ctx, token, "id", "user2")
+	if StatusCode(err) != http.StatusServiceUnavailable {
+		t.Error(err)
+	}
+	if calls := control.recorded(); len(calls) != 1 {
+		t.Error("unexpected retry of post without Idempotency-Key", len(calls))
+	}
+
+	// POST with Idempotency-Key
+	control.fail(http.StatusServiceUnavailable)
+	created, err := c.CreateInstanceWithIdempotencyKey(ctx, token, model.Instance{Name: "test"}, "key1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := control.recorded()[1:]
+	if len(calls) != 2 {
+		t.Fatal("expected one retry", len(calls))
+	}
+	for _, c := range calls {
+		if c.Method != "CreateInstance" || c.IdempotencyKey != "key1" {
+			t.Error(c)
+		}
+	}
+	if created.Id == "" {
+		t.Error(created)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/api"
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// fakeController keeps instances in memory and records the calls of the api handlers.
+// Methods not needed by the tests are not implemented and panic.
+type fakeController struct {
+	api.Controller
+	mux       sync.Mutex
+	instances map[string]model.Instance
+	calls     []call
+	failures  []int // status codes returned by the next calls, before the call is executed
+}
+
+type call struct {
+	Method         string
+	Id             string
+	User           string // id of the user of the token passed by the api
+	Filter         model.InstanceFilter
+	IdempotencyKey string
+}
+
+func newFakeController() *fakeController {
+	return &fakeController{instances: map[string]model.Instance{}}
+}
+
+// record stores the call and returns the next injected failure
+func (this *fakeController) record(c call) (err error, code int) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.calls = append(this.calls, c)
+	if len(this.failures) > 0 {
+		code = this.failures[0]
+		this.failures = this.failures[1:]
+		return errors.New("injected failure " + strconv.Itoa(code)), code
+	}
+	return nil, http.StatusOK
+}
+
+func (this *fakeController) fail(codes ...int) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.failures = append(this.failures, codes...)
+}
+
+func (this *fakeController) recorded() []call {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return append([]call{}, this.calls...)
+}
+
+func (this *fakeController) count(method string) (result int) {
+	for _, c := range this.recorded() {
+		if c.Method == method {
+			result++
+		}
+	}
+	return result
+}
+
+func (this *fakeController) ImpersonateUser(ctx context.Context, token jwt.Token, userId string) (context.Context, jwt.Token, error, int) {
+	if !token.IsAdmin() {
+		return ctx, token, errors.New("for_user is only allowed for admins"), http.StatusForbidden
+	}
+	return ctx, testToken(userId), nil, http.StatusOK
+}
+
+func (this *fakeController) ListInstances(_ context.Context, token jwt.Token, limit int64, offset int64, _ string, _ bool, filter model.InstanceFilter, _ *model.InstanceCursor, _ bool) ([]model.Instance, error, int) {
+	err, code := this.record(call{Method: "ListInstances", User: token.GetUserId(), Filter: filter})
+	if err != nil {
+		return nil, err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	results := []model.Instance{}
+	for _, instance := range this.instances {
+		if instance.Owner == token.GetUserId() {
+			results = append(results, instance)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	results = results[min(offset, int64(len(results))):]
+	return results[:min(limit, int64(len(results)))], nil, http.StatusOK
+}
+
+func (this *fakeController) CountInstances(_ context.Context, token jwt.Token, filter model.InstanceFilter) (int64, error, int) {
+	err, code := this.record(call{Method: "CountInstances", User: token.GetUserId(), Filter: filter})
+	if err != nil {
+		return 0, err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	var count int64
+	for _, instance := range this.instances {
+		if instance.Owner == token.GetUserId() {
+			count++
+		}
+	}
+	return count, nil, http.StatusOK
+}
+
+func (this *fakeController) ReadInstance(_ context.Context, id string, token jwt.Token) (model.Instance, error, int) {
+	err, code := this.record(call{Method: "ReadInstance", Id: id, User: token.GetUserId()})
+	if err != nil {
+		return model.Instance{}, err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok := this.instances[id]
+	if !ok || instance.Owner != token.GetUserId() {
+		return instance, errors.New("not found"), http.StatusNotFound
+	}
+	return instance, nil, http.StatusOK
+}
+
+func (this *fakeController) GetInstanceLogs(ctx context.Context, id string, token jwt.Token, _ int64) (string, error, int) {
+	_, err, code := this.ReadInstance(ctx, id, token)
+	if err != nil {
+		return "", err, code
+	}
+	return "logs of " + id, nil, http.StatusOK
+}
+
+func (this *fakeController) CreateInstance(_ context.Context, instance model.Instance, token jwt.Token, idempotencyKey string) (model.Instance, error, int) {
+	err, code := this.record(call{Method: "CreateInstance", User: token.GetUserId(), IdempotencyKey: idempotencyKey})
+	if err != nil {
+		return instance, err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance.Id = "urn:infai:ses:import:" + strconv.Itoa(len(this.instances)+1)
+	instance.Owner = token.GetUserId()
+	instance.Version = 1
+	this.instances[instance.Id] = instance
+	return instance, nil, http.StatusOK
+}
+
+func (this *fakeController) SetInstance(_ context.Context, instance model.Instance, token jwt.Token, expectedVersion *int64) (error, int) {
+	err, code := this.record(call{Method: "SetInstance", Id: instance.Id, User: token.GetUserId()})
+	if err != nil {
+		return err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	existing, ok := this.instances[instance.Id]
+	if !ok || existing.Owner != token.GetUserId() {
+		return errors.New("not found"), http.StatusNotFound
+	}
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		return errors.New("instance was modified"), http.StatusPreconditionFailed
+	}
+	instance.Owner = existing.Owner
+	instance.Version = existing.Version + 1
+	this.instances[instance.Id] = instance
+	return nil, http.StatusOK
+}
+
+func (this *fakeController) DeleteInstance(_ context.Context, id string, token jwt.Token, expectedVersion *int64) (error, int) {
+	err, code := this.record(call{Method: "DeleteInstance", Id: id, User: token.GetUserId()})
+	if err != nil {
+		return err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	existing, ok := this.instances[id]
+	if !ok || existing.Owner != token.GetUserId() {
+		return errors.New("not found"), http.StatusNotFound
+	}
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		return errors.New("instance was modified"), http.StatusPreconditionFailed
+	}
+	delete(this.instances, id)
+	return nil, http.StatusNoContent
+}
+
+func (this *fakeController) TransferInstance(_ context.Context, id string, newOwner string, token jwt.Token) (error, int) {
+	err, code := this.record(call{Method: "TransferInstance", Id: id, User: token.GetUserId()})
+	if err != nil {
+		return err, code
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	existing, ok := this.instances[id]
+	if !ok || existing.Owner != token.GetUserId() {
+		return errors.New("not found"), http.StatusNotFound
+	}
+	existing.Owner = newOwner
+	this.instances[id] = existing
+	return nil, http.StatusOK
+}
+
+// newTestServer runs the api handlers with control
+func newTestServer(t *testing.T, control api.Controller) *httptest.Server {
+	handler, err := api.NewHandler(config.Config{}, control, metrics.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testToken returns an unsigned token; the api parses tokens without validation
+func testToken(userId string, roles ...string) jwt.Token {
+	encode := func(value any) string {
+		b, _ := json.Marshal(value)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	raw := encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." +
+		encode(map[string]any{"sub": userId, "realm_access": map[string][]string{"roles": append(roles, "user")}}) + ".c2lnbmF0dXJl"
+	token, err := jwt.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+func jwtWithoutToken() jwt.Token {
+	return jwt.Token{}
+}
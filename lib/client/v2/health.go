@@ -0,0 +1,40 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Client) Live(ctx context.Context) (result model.Health, err error) {
+	_, err = this.doJson(ctx, jwt.Token{}, request{method: http.MethodGet, path: []string{"health", "live"}}, &result)
+	return result, err
+}
+
+// Ready is not retried; a failing dependency is reported by ready=false and the checks of result
+func (this *Client) Ready(ctx context.Context) (result model.Health, ready bool, err error) {
+	_, body, _, err := this.send(ctx, jwt.Token{}, request{method: http.MethodGet, path: []string{"health", "ready"}}, nil)
+	if err != nil && StatusCode(err) != http.StatusServiceUnavailable {
+		return result, false, err
+	}
+	err = json.Unmarshal(body, &result)
+	return result, result.Status == model.HealthOk, err
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+// ListOptions are used by ListInstances and CountInstances. Zero values use the defaults of the api.
+type ListOptions struct {
+	Limit            int64
+	Offset           int64
+	Cursor           string // next_cursor of the previous page, replaces Offset
+	Sort             string // field to sort by
+	Desc             bool
+	Filter           model.InstanceFilter
+	IncludeDataStats bool // ignored by CountInstances
+}
+
+func (this ListOptions) query() url.Values {
+	query := filterQuery(this.Filter)
+	if this.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(this.Limit, 10))
+	}
+	if this.Offset > 0 {
+		query.Set("offset", strconv.FormatInt(this.Offset, 10))
+	}
+	if this.Cursor != "" {
+		query.Set("cursor", this.Cursor)
+	}
+	if this.Sort != "" {
+		if this.Desc {
+			query.Set("sort", this.Sort+".desc")
+		} else {
+			query.Set("sort", this.Sort+".asc")
+		}
+	}
+	return query
+}
+
+func filterQuery(filter model.InstanceFilter) url.Values {
+	query := url.Values{}
+	setIfNotEmpty := func(key string, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	setIfNotEmpty("search", filter.Search)
+	setIfNotEmpty("owner", filter.Owner)
+	setIfNotEmpty("import_type_id", filter.ImportTypeId)
+	setIfNotEmpty("image", filter.Image)
+	if filter.Restart != nil {
+		query.Set("restart", strconv.FormatBool(*filter.Restart))
+	}
+	if filter.Generated != nil {
+		query.Set("generated", strconv.FormatBool(*filter.Generated))
+	}
+	for key, value := range map[string]*time.Time{
+		"created_after":  filter.CreatedAfter,
+		"created_before": filter.CreatedBefore,
+		"updated_after":  filter.UpdatedAfter,
+		"updated_before": filter.UpdatedBefore,
+	} {
+		if value != nil {
+			query.Set(key, value.Format(time.RFC3339Nano))
+		}
+	}
+	for key, value := range filter.Labels {
+		query.Add("label", key+":"+value)
+	}
+	return query
+}
+
+// ListInstances returns a page of instances and the total count. Pass result.NextCursor as ListOptions.Cursor to read the next page.
+func (this *Client) ListInstances(ctx context.Context, token jwt.Token, options ListOptions) (result model.InstanceList, err error) {
+	query := options.query()
+	if options.IncludeDataStats {
+		query.Set("include_data_stats", "true")
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"v2", "instances"}, query: query}, &result)
+	return result, err
+}
+
+func (this *Client) CountInstances(ctx context.Context, token jwt.Token, options ListOptions) (count int64, err error) {
+	query := options.query()
+	query.Del("limit")
+	query.Del("offset")
+	query.Del("sort")
+	_, body, err := this.do(ctx, token, request{method: http.MethodGet, path: []string{"total", "instances"}, query: query})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(body), 10, 64)
+}
+
+func (this *Client) ReadInstance(ctx context.Context, token jwt.Token, id string) (result model.Instance, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"v2", "instances", id}}, &result)
+	return result, err
+}
+
+func (this *Client) GetInstanceDataStats(ctx context.Context, token jwt.Token, id string) (result model.DataStats, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"instances", id, "data-stats"}}, &result)
+	return result, err
+}
+
+// PreviewInstance returns the latest n messages of the instance topic; n <= 0 uses the default of the api
+func (this *Client) PreviewInstance(ctx context.Context, token jwt.Token, id string, n int64) (result []model.PreviewRecord, err error) {
+	query := url.Values{}
+	if n > 0 {
+		query.Set("n", strconv.FormatInt(n, 10))
+	}
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"instances", id, "preview"}, query: query}, &result)
+	return result, err
+}
+
+// GetInstanceLogs returns the last tail log lines of the instance container; tail <= 0 uses the default of the api
+func (this *Client) GetInstanceLogs(ctx context.Context, token jwt.Token, id string, tail int64) (logs string, err error) {
+	query := url.Values{}
+	if tail > 0 {
+		query.Set("tail", strconv.FormatInt(tail, 10))
+	}
+	_, body, err := this.do(ctx, token, request{method: http.MethodGet, path: []string{"instances", id, "logs"}, query: query})
+	return string(body), err
+}
+
+// CreateInstance sends a random Idempotency-Key, so that the request is retried like idempotent requests
+// without creating duplicates.
+func (this *Client) CreateInstance(ctx context.Context, token jwt.Token, instance model.Instance) (result model.Instance, err error) {
+	return this.CreateInstanceWithIdempotencyKey(ctx, token, instance, uuid.NewString())
+}
+
+// CreateInstanceWithIdempotencyKey lets callers retry the creation across restarts by persisting the key
+func (this *Client) CreateInstanceWithIdempotencyKey(ctx context.Context, token jwt.Token, instance model.Instance, key string) (result model.Instance, err error) {
+	headers := http.Header{"Idempotency-Key": []string{key}}
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"v2", "instances"}, body: instance, headers: headers}, &result)
+	return result, err
+}
+
+// SetInstance returns the stored instance
+func (this *Client) SetInstance(ctx context.Context, token jwt.Token, instance model.Instance) (result model.Instance, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPut, path: []string{"v2", "instances", instance.Id}, body: instance}, &result)
+	return result, err
+}
+
+// MergePatchInstance applies a JSON Merge Patch to {name, configs: {config name: value}, restart, labels} of the instance
+func (this *Client) MergePatchInstance(ctx context.Context, token jwt.Token, id string, patch any) (result model.Instance, err error) {
+	headers := http.Header{"Content-Type": []string{"application/merge-patch+json"}}
+	_, err = this.doJson(ctx, token, request{method: http.MethodPatch, path: []string{"v2", "instances", id}, body: patch, headers: headers}, &result)
+	return result, err
+}
+
+// PatchOperation is an operation of a JSON Patch, e.g. {Op: "replace", Path: "/configs/url", Value: "..."}
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// JsonPatchInstance applies a JSON Patch to {name, configs: {config name: value}, restart, labels} of the instance
+func (this *Client) JsonPatchInstance(ctx context.Context, token jwt.Token, id string, operations []PatchOperation) (result model.Instance, err error) {
+	headers := http.Header{"Content-Type": []string{"application/json-patch+json"}}
+	_, err = this.doJson(ctx, token, request{method: http.MethodPatch, path: []string{"v2", "instances", id}, body: operations, headers: headers}, &result)
+	return result, err
+}
+
+// SetInstanceIfUnchanged updates the instance only if it is still at instance.Version, otherwise IsPreconditionFailed(err) is true
+func (this *Client) SetInstanceIfUnchanged(ctx context.Context, token jwt.Token, instance model.Instance) (result model.Instance, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPut, path: []string{"v2", "instances", instance.Id}, body: instance, headers: ifMatch(instance.Version)}, &result)
+	return result, err
+}
+
+// DeleteInstanceIfUnchanged deletes the instance only if it is still at version, otherwise IsPreconditionFailed(err) is true
+func (this *Client) DeleteInstanceIfUnchanged(ctx context.Context, token jwt.Token, id string, version int64) (err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodDelete, path: []string{"v2", "instances", id}, headers: ifMatch(version)}, nil)
+	return err
+}
+
+func ifMatch(version int64) http.Header {
+	return http.Header{"If-Match": []string{`"` + strconv.FormatInt(version, 10) + `"`}}
+}
+
+func (this *Client) DeleteInstance(ctx context.Context, token jwt.Token, id string) (err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodDelete, path: []string{"v2", "instances", id}}, nil)
+	return err
+}
+
+func (this *Client) TransferInstance(ctx context.Context, token jwt.Token, id string, newOwner string) (err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"instances", id, "transfer"}, body: model.TransferRequest{NewOwner: newOwner}}, nil)
+	return err
+}
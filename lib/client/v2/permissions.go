@@ -0,0 +1,35 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Client) GetInstancePermissions(ctx context.Context, token jwt.Token, id string) (result permV2Client.ResourcePermissions, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"instances", id, "permissions"}}, &result)
+	return result, err
+}
+
+// SetInstancePermissions replaces all permissions of the instance; the owner has to keep all rights
+func (this *Client) SetInstancePermissions(ctx context.Context, token jwt.Token, id string, permissions permV2Client.ResourcePermissions) (result permV2Client.ResourcePermissions, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPut, path: []string{"instances", id, "permissions"}, body: permissions}, &result)
+	return result, err
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	v1 "github.com/SENERGY-Platform/import-deploy/lib/client"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+// TestV1Client covers the fixed requests of the v1 client: PUT on update, the for_user query, /total/instances and escaping
+func TestV1Client(t *testing.T) {
+	control := newFakeController()
+	server := newTestServer(t, control)
+	c := v1.NewClient(server.URL)
+	user := testToken("user1")
+	admin := testToken("admin1", "admin")
+
+	created, err, _ := c.CreateInstance(model.Instance{Name: "test"}, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created.Name = "updated"
+	err, code := c.SetInstance(created, user)
+	if err != nil {
+		t.Fatal(err, code)
+	}
+	if calls := control.count("SetInstance"); calls != 1 {
+		t.Error("update not sent as PUT /instances/:id", calls)
+	}
+
+	read, err, _ := c.ReadInstance(created.Id, admin, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Name != "updated" {
+		t.Error(read)
+	}
+
+	list, err, _ := c.ListInstances(admin, 10, 0, "name", true, "a&b=c #d", true, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Error(list)
+	}
+
+	count, err, _ := c.CountInstances(user, "a&b=c", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error(count)
+	}
+
+	_, err, code = c.ReadInstance("a b%c?d", user, "")
+	if code != http.StatusNotFound {
+		t.Error(err, code)
+	}
+
+	err, code = c.DeleteInstance(created.Id, admin, "user1")
+	if err != nil {
+		t.Fatal(err, code)
+	}
+
+	for _, call := range control.recorded() {
+		switch call.Method {
+		case "ListInstances", "CountInstances":
+			if call.Filter.Search != "a&b=c #d" && call.Filter.Search != "a&b=c" {
+				t.Error("search not escaped", call.Filter.Search)
+			}
+		case "ReadInstance":
+			if call.Id != created.Id && call.Id != "a b%c?d" {
+				t.Error("id not escaped", call.Id)
+			}
+		}
+		if call.User != "user1" {
+			t.Error("for_user not applied", call)
+		}
+	}
+	if calls := control.count("CountInstances"); calls != 1 {
+		t.Error("count not sent to /total/instances", calls)
+	}
+	if calls := control.count("DeleteInstance"); calls != 1 {
+		t.Error(calls)
+	}
+}
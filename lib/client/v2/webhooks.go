@@ -0,0 +1,68 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Client) ListWebhooks(ctx context.Context, token jwt.Token, limit int64, offset int64) (result []model.Webhook, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"webhooks"}, query: pagination(limit, offset)}, &result)
+	return result, err
+}
+
+func (this *Client) ReadWebhook(ctx context.Context, token jwt.Token, id string) (result model.Webhook, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"webhooks", id}}, &result)
+	return result, err
+}
+
+// CreateWebhook returns the created webhook including its secret, which is not returned by any other call
+func (this *Client) CreateWebhook(ctx context.Context, token jwt.Token, webhook model.Webhook) (result model.Webhook, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPost, path: []string{"webhooks"}, body: webhook}, &result)
+	return result, err
+}
+
+func (this *Client) SetWebhook(ctx context.Context, token jwt.Token, webhook model.Webhook) (err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodPut, path: []string{"webhooks", webhook.Id}, body: webhook}, nil)
+	return err
+}
+
+func (this *Client) DeleteWebhook(ctx context.Context, token jwt.Token, id string) (err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodDelete, path: []string{"webhooks", id}}, nil)
+	return err
+}
+
+func (this *Client) ListWebhookDeliveries(ctx context.Context, token jwt.Token, id string, limit int64, offset int64) (result []model.WebhookDelivery, err error) {
+	_, err = this.doJson(ctx, token, request{method: http.MethodGet, path: []string{"webhooks", id, "deliveries"}, query: pagination(limit, offset)}, &result)
+	return result, err
+}
+
+func pagination(limit int64, offset int64) url.Values {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.FormatInt(limit, 10))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	return query
+}
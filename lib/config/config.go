@@ -28,31 +28,58 @@ import (
 )
 
 type Config struct {
-	ServerPort                            string `json:"server_port"`
-	JwtPubRsa                             string `json:"jwt_pub_rsa"`
-	MongoUrl                              string `json:"mongo_url" config:"secret"`
-	MongoReplSet                          bool   `json:"mongo_repl_set"` //set true if mongodb is configured as replication set or mongos and is able to handle transactions
-	MongoTable                            string `json:"mongo_table"`
-	MongoImportTypeCollection             string `json:"mongo_import_type_collection"`
-	ImportRepoUrl                         string `json:"import_repo_url"`
-	KafkaBootstrap                        string `json:"kafka_bootstrap"`
-	DeployMode                            string `json:"deploy_mode"`
-	DockerNetwork                         string `json:"docker_network"`
-	DockerPull                            bool   `json:"docker_pull"`
-	RancherUrl                            string `json:"rancher_url"`
-	RancherAccessKey                      string `json:"rancher_access_key" config:"secret"`
-	RancherSecretKey                      string `json:"rancher_secret_key" config:"secret"`
-	RancherStackId                        string `json:"rancher_stack_id"`
-	RancherNamespaceId                    string `json:"rancher_namespace_id"`
-	RancherProjectId                      string `json:"rancher_project_id"`
-	KafkaReplication                      int64  `json:"kafka_replication"`
-	Debug                                 bool   `json:"debug"`
-	StartupEnsureDeployed                 bool   `json:"startup_ensure_deployed"`
-	PermissionV2Url                       string `json:"permission_v2_url"`
-	MigrationUpdateAllInstancePermissions bool   `json:"migration_update_all_instance_permissions"`
-	KubeConfig                            string `json:"kube_config"`
-	SkipMigration                         bool   `json:"skip_migration"`
-	SkipKafkaAdmin                        bool   `json:"skip_kafka_admin"`
+	ServerPort                            string  `json:"server_port"`
+	JwtPubRsa                             string  `json:"jwt_pub_rsa"`
+	MongoUrl                              string  `json:"mongo_url" config:"secret"`
+	MongoReplSet                          bool    `json:"mongo_repl_set"` //set true if mongodb is configured as replication set or mongos and is able to handle transactions
+	MongoTable                            string  `json:"mongo_table"`
+	MongoImportTypeCollection             string  `json:"mongo_import_type_collection"`
+	MongoEventOutboxCollection            string  `json:"mongo_event_outbox_collection"`
+	MongoWebhookCollection                string  `json:"mongo_webhook_collection"`
+	MongoWebhookDeliveryCollection        string  `json:"mongo_webhook_delivery_collection"`
+	MongoIdempotencyCollection            string  `json:"mongo_idempotency_collection"`
+	ImportRepoUrl                         string  `json:"import_repo_url"`
+	KafkaBootstrap                        string  `json:"kafka_bootstrap"`
+	DeployMode                            string  `json:"deploy_mode"`
+	DockerNetwork                         string  `json:"docker_network"`
+	DockerPull                            bool    `json:"docker_pull"`
+	RancherUrl                            string  `json:"rancher_url"`
+	RancherAccessKey                      string  `json:"rancher_access_key" config:"secret"`
+	RancherSecretKey                      string  `json:"rancher_secret_key" config:"secret"`
+	RancherStackId                        string  `json:"rancher_stack_id"`
+	RancherNamespaceId                    string  `json:"rancher_namespace_id"`
+	RancherProjectId                      string  `json:"rancher_project_id"`
+	KafkaReplication                      int64   `json:"kafka_replication"`
+	InstanceEventsTopic                   string  `json:"instance_events_topic"` //empty string disables publishing of instance events
+	Debug                                 bool    `json:"debug"`
+	StartupEnsureDeployed                 bool    `json:"startup_ensure_deployed"`
+	PermissionV2Url                       string  `json:"permission_v2_url"`
+	MigrationUpdateAllInstancePermissions bool    `json:"migration_update_all_instance_permissions"`
+	KubeConfig                            string  `json:"kube_config"`
+	SkipMigration                         bool    `json:"skip_migration"`
+	SkipKafkaAdmin                        bool    `json:"skip_kafka_admin"`
+	StaleCheckInterval                    string  `json:"stale_check_interval"`   //empty string disables the stale check
+	RuntimeCheckInterval                  string  `json:"runtime_check_interval"` //empty string disables the runtime check
+	NotificationUrl                       string  `json:"notification_url"`
+	PreviewMaxRecords                     int64   `json:"preview_max_records"`
+	PreviewMaxRecordBytes                 int64   `json:"preview_max_record_bytes"`
+	PreviewTimeout                        string  `json:"preview_timeout"`
+	LogsMaxLines                          int64   `json:"logs_max_lines"`
+	WebhookAllowedNetworks                string  `json:"webhook_allowed_networks"` //comma separated CIDRs of internal networks, which may be called by webhooks
+	WebhookMaxAttempts                    int64   `json:"webhook_max_attempts"`
+	WebhookTimeout                        string  `json:"webhook_timeout"`
+	ShutdownTimeout                       string  `json:"shutdown_timeout"` //max time to wait for in-flight http requests on shutdown
+	HealthCheckTimeout                    string  `json:"health_check_timeout"`
+	TracingExporter                       string  `json:"tracing_exporter"` //"otlp", "stdout" or empty string to disable exporting of traces
+	TracingOtlpEndpoint                   string  `json:"tracing_otlp_endpoint"`
+	TracingServiceName                    string  `json:"tracing_service_name"`
+	TracingSampleRatio                    float64 `json:"tracing_sample_ratio"`
+	KeycloakUrl                           string  `json:"keycloak_url"` //used to exchange tokens for the for_user parameter of admins; empty string disables for_user
+	KeycloakClientId                      string  `json:"keycloak_client_id"`
+	KeycloakClientSecret                  string  `json:"keycloak_client_secret" config:"secret"`
+	IdempotencyKeyTtl                     string  `json:"idempotency_key_ttl"`   //how long Idempotency-Key headers of create requests are remembered
+	SecretConfigPattern                   string  `json:"secret_config_pattern"` //regular expression matching names of configs, which are only exported on request
+	CursorSecret                          string  `json:"cursor_secret" config:"secret"`
 }
 
 // loads config from json in location and used environment variables (e.g ZookeeperUrl --> ZOOKEEPER_URL)
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// ApplyManifest plans the changes needed to reach the manifest, compared to the instances owned by the user.
+// Instances are matched by their model.ApplyKeyLabel. The plan is validated completely and only executed
+// if no step has an error; execution errors of single steps are reported and do not stop the others.
+func (this *Controller) ApplyManifest(ctx context.Context, jwt jwt.Token, manifest model.ApplyManifest, options model.ApplyOptions) (result model.ApplyResult, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.ApplyManifest")
+	defer tracing.End(span, &err)
+	keys := map[string]bool{}
+	for _, instance := range manifest.Instances {
+		if instance.Key == "" {
+			return result, errors.New("missing key of instance " + instance.Name), http.StatusBadRequest
+		}
+		if keys[instance.Key] {
+			return result, errors.New("duplicate key " + instance.Key), http.StatusBadRequest
+		}
+		if value, ok := instance.Labels[model.ApplyKeyLabel]; ok && value != instance.Key {
+			return result, errors.New("the label " + model.ApplyKeyLabel + " is reserved for the key"), http.StatusBadRequest
+		}
+		keys[instance.Key] = true
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	existing, err := this.db.ListInstances(timeoutCtx, -1, 0, "name", jwt, true, model.InstanceFilter{Owner: jwt.GetUserId()}, nil)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	byKey := map[string][]model.Instance{}
+	for _, instance := range existing {
+		if key := instance.Labels[model.ApplyKeyLabel]; key != "" {
+			byKey[key] = append(byKey[key], instance)
+		}
+	}
+
+	result = model.ApplyResult{DryRun: options.DryRun, Steps: []model.ApplyStep{}}
+	instances := []model.Instance{} // desired state of each step
+	valid := true
+	for _, manifestInstance := range manifest.Instances {
+		step, instance := this.planApply(ctx, jwt, manifestInstance, byKey[manifestInstance.Key])
+		valid = valid && step.Error == ""
+		result.Steps = append(result.Steps, step)
+		instances = append(instances, instance)
+	}
+	if options.Prune {
+		for _, instance := range existing {
+			if keys[instance.Labels[model.ApplyKeyLabel]] || instance.Generated {
+				continue
+			}
+			result.Steps = append(result.Steps, model.ApplyStep{
+				Key:        instance.Labels[model.ApplyKeyLabel],
+				Action:     model.ApplyActionDelete,
+				InstanceId: instance.Id,
+				Name:       instance.Name,
+			})
+			instances = append(instances, instance)
+		}
+	}
+	if options.DryRun || !valid {
+		return result, nil, http.StatusOK
+	}
+
+	for i, step := range result.Steps {
+		instance := instances[i]
+		switch step.Action {
+		case model.ApplyActionCreate:
+			created, err, _ := this.CreateInstance(ctx, instance, jwt, "")
+			if err != nil {
+				result.Steps[i].Error = err.Error()
+			} else {
+				result.Steps[i].InstanceId = created.Id
+			}
+		case model.ApplyActionUpdate:
+			err, _ := this.SetInstance(ctx, instance, jwt, &instance.Version)
+			if err != nil {
+				result.Steps[i].Error = err.Error()
+			}
+		case model.ApplyActionDelete:
+			err, _ := this.DeleteInstance(ctx, instance.Id, jwt, &instance.Version)
+			if err != nil {
+				result.Steps[i].Error = err.Error()
+			}
+		}
+	}
+	result.Applied = true
+	return result, nil, http.StatusOK
+}
+
+// planApply returns the step and the instance to create or update
+func (this *Controller) planApply(ctx context.Context, jwt jwt.Token, manifestInstance model.ManifestInstance, existing []model.Instance) (step model.ApplyStep, instance model.Instance) {
+	step = model.ApplyStep{Key: manifestInstance.Key, Action: model.ApplyActionCreate, Name: manifestInstance.Name}
+	labels := maps.Clone(manifestInstance.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[model.ApplyKeyLabel] = manifestInstance.Key
+	instance = model.Instance{
+		Name:             manifestInstance.Name,
+		ImportTypeId:     manifestInstance.ImportTypeId,
+		Configs:          slices.Clone(manifestInstance.Configs),
+		Restart:          manifestInstance.Restart,
+		ExpectedInterval: manifestInstance.ExpectedInterval,
+		Labels:           labels,
+		Owner:            jwt.GetUserId(),
+	}
+	if len(existing) > 1 {
+		step.Error = "more than one instance has this key"
+		return step, instance
+	}
+	if len(existing) == 1 {
+		step.Action = model.ApplyActionUpdate
+		step.InstanceId = existing[0].Id
+		if existing[0].ImportTypeId != instance.ImportTypeId {
+			step.Error = "change of import type not supported, use a new key"
+			return step, instance
+		}
+		instance.Id = existing[0].Id
+		instance.Version = existing[0].Version
+	}
+
+	filled, err, _ := this.fillDefaultValues(ctx, model.Instance{
+		Id:               instance.Id,
+		ImportTypeId:     instance.ImportTypeId,
+		Configs:          slices.Clone(instance.Configs),
+		Restart:          instance.Restart,
+		ExpectedInterval: instance.ExpectedInterval,
+		Labels:           instance.Labels,
+	}, jwt)
+	if err != nil {
+		step.Error = err.Error()
+		return step, instance
+	}
+	access, err := this.hasXAccess(ctx, jwt, instance.ImportTypeId)
+	if err != nil {
+		step.Error = err.Error()
+		return step, instance
+	}
+	if !access {
+		step.Error = "no execute access to importType"
+		return step, instance
+	}
+
+	if step.Action == model.ApplyActionUpdate {
+		desired := existing[0]
+		desired.Name = instance.Name
+		desired.Configs = filled.Configs
+		desired.Restart = filled.Restart
+		desired.ExpectedInterval = filled.ExpectedInterval
+		desired.Labels = instance.Labels
+		step.ChangedFields = changedFields(sortedConfigs(existing[0]), sortedConfigs(desired))
+		if len(step.ChangedFields) == 0 {
+			step.Action = model.ApplyActionUnchanged
+		}
+	}
+	return step, instance
+}
+
+// sortedConfigs allows to compare configs independent of their order
+func sortedConfigs(instance model.Instance) model.Instance {
+	instance.Configs = slices.Clone(instance.Configs)
+	slices.SortFunc(instance.Configs, func(a, b model.InstanceConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return instance
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// ExportInstances returns the definitions of the instances matching the filter, restricted to ids if not empty.
+// Configs with names matching SecretConfigPattern are omitted unless includeSecrets is set.
+func (this *Controller) ExportInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter, ids []string, includeSecrets bool) (result model.InstanceBundle, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.ExportInstances")
+	defer tracing.End(span, &err)
+	secret, err := regexp.Compile(this.config.SecretConfigPattern)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instances, err := this.db.ListInstances(timeoutCtx, -1, 0, "name", jwt, true, filter, nil)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	result = model.InstanceBundle{Version: model.InstanceBundleVersion, ExportedAt: time.Now(), Instances: []model.InstanceDefinition{}}
+	for _, instance := range instances {
+		if len(ids) > 0 && !slices.Contains(ids, instance.Id) {
+			continue
+		}
+		definition := model.InstanceDefinition{
+			Name:             instance.Name,
+			ImportTypeId:     instance.ImportTypeId,
+			Configs:          []model.InstanceConfig{},
+			Restart:          instance.Restart,
+			ExpectedInterval: instance.ExpectedInterval,
+			Labels:           instance.Labels,
+		}
+		for _, config := range instance.Configs {
+			if !includeSecrets && secret.MatchString(config.Name) {
+				definition.OmittedConfigs = append(definition.OmittedConfigs, config.Name)
+				continue
+			}
+			definition.Configs = append(definition.Configs, config)
+		}
+		result.Instances = append(result.Instances, definition)
+	}
+	return result, nil, http.StatusOK
+}
+
+// ImportInstances creates or updates the instances of the bundle. Instances of the user with the same name are conflicts,
+// which are handled as selected by options.OnConflict. Failures of single definitions are reported and do not stop the import.
+// With options.DryRun, every definition is validated, but nothing is changed.
+func (this *Controller) ImportInstances(ctx context.Context, jwt jwt.Token, bundle model.InstanceBundle, options model.ImportOptions) (result model.ImportReport, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.ImportInstances")
+	defer tracing.End(span, &err)
+	if bundle.Version != model.InstanceBundleVersion {
+		return result, errors.New("unsupported bundle version"), http.StatusBadRequest
+	}
+	if options.OnConflict == "" {
+		options.OnConflict = model.OnConflictFail
+	}
+	if !slices.Contains([]model.OnConflict{model.OnConflictFail, model.OnConflictSkip, model.OnConflictUpdate, model.OnConflictCreate}, options.OnConflict) {
+		return result, errors.New("unknown on_conflict value"), http.StatusBadRequest
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	existing, err := this.db.ListInstances(timeoutCtx, -1, 0, "name", jwt, true, model.InstanceFilter{}, nil)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	byName := map[string][]model.Instance{}
+	for _, instance := range existing {
+		byName[instance.Name] = append(byName[instance.Name], instance)
+	}
+
+	conflicts := []string{}
+	for _, definition := range bundle.Instances {
+		if len(byName[definition.Name]) > 0 {
+			conflicts = append(conflicts, definition.Name)
+		}
+	}
+	// a dry run reports the conflicts per definition instead
+	if len(conflicts) > 0 && options.OnConflict == model.OnConflictFail && !options.DryRun {
+		return result, errors.New("instances with these names exist already: " + strings.Join(conflicts, ", ")), http.StatusConflict
+	}
+
+	result = model.ImportReport{DryRun: options.DryRun, Results: []model.ImportResult{}}
+	for _, definition := range bundle.Instances {
+		result.Results = append(result.Results, this.importInstance(ctx, jwt, definition, byName[definition.Name], options))
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Controller) importInstance(ctx context.Context, jwt jwt.Token, definition model.InstanceDefinition, existing []model.Instance, options model.ImportOptions) (result model.ImportResult) {
+	importTypeId := definition.ImportTypeId
+	if mapped, ok := options.ImportTypeMapping[importTypeId]; ok {
+		importTypeId = mapped
+	}
+	result = model.ImportResult{Name: definition.Name, ImportTypeId: importTypeId}
+	fail := func(err error) model.ImportResult {
+		result.Action = model.ImportActionError
+		result.Error = err.Error()
+		return result
+	}
+
+	instance := model.Instance{
+		Name:             definition.Name,
+		ImportTypeId:     importTypeId,
+		Configs:          definition.Configs,
+		Restart:          definition.Restart,
+		ExpectedInterval: definition.ExpectedInterval,
+		Labels:           definition.Labels,
+	}
+	result.Action = model.ImportActionCreate
+	if len(existing) > 0 {
+		switch options.OnConflict {
+		case model.OnConflictFail:
+			// only reached by dry runs, other imports are rejected as a whole
+			result.InstanceId = existing[0].Id
+			return fail(errors.New("an instance with this name exists already"))
+		case model.OnConflictSkip:
+			result.Action = model.ImportActionSkip
+			result.InstanceId = existing[0].Id
+			return result
+		case model.OnConflictUpdate:
+			if len(existing) > 1 {
+				return fail(errors.New("more than one instance with this name exists"))
+			}
+			current := existing[0]
+			if current.ImportTypeId != importTypeId {
+				return fail(errors.New("the existing instance uses the import type " + current.ImportTypeId))
+			}
+			result.Action = model.ImportActionUpdate
+			result.InstanceId = current.Id
+			// configs which are not part of the definition, e.g. omitted secrets, keep their current value
+			configs := slices.Clone(current.Configs)
+			for _, config := range definition.Configs {
+				idx := slices.IndexFunc(configs, func(c model.InstanceConfig) bool { return c.Name == config.Name })
+				if idx < 0 {
+					configs = append(configs, config)
+				} else {
+					configs[idx] = config
+				}
+			}
+			instance.Id = current.Id
+			instance.Configs = configs
+			instance.Owner = current.Owner
+			instance.Version = current.Version
+		}
+	}
+	for _, name := range definition.OmittedConfigs {
+		if result.Action == model.ImportActionCreate {
+			result.Warnings = append(result.Warnings, "config "+name+" was not exported and is set to its default value")
+		} else {
+			result.Warnings = append(result.Warnings, "config "+name+" was not exported and keeps its current value")
+		}
+	}
+
+	if options.DryRun {
+		if instance.Owner == "" {
+			instance.Owner = jwt.GetUserId()
+		}
+		_, err, _ := this.fillDefaultValues(ctx, instance, jwt)
+		if err != nil {
+			return fail(err)
+		}
+		access, err := this.hasXAccess(ctx, jwt, instance.ImportTypeId)
+		if err != nil {
+			return fail(err)
+		}
+		if !access {
+			return fail(errors.New("no execute access to importType"))
+		}
+		return result
+	}
+	if result.Action == model.ImportActionUpdate {
+		err, _ := this.SetInstance(ctx, instance, jwt, &instance.Version)
+		if err != nil {
+			return fail(err)
+		}
+		return result
+	}
+	created, err, _ := this.CreateInstance(ctx, instance, jwt, "")
+	if err != nil {
+		return fail(err)
+	}
+	result.InstanceId = created.Id
+	return result
+}
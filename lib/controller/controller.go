@@ -17,8 +17,14 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"sync"
+
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
 	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/keycloak"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
 	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 )
 
@@ -28,14 +34,48 @@ type Controller struct {
 	kafkaAdmin       KafkaAdmin
 	config           config.Config
 	permv2           permV2Client.Client
+	events           []EventPublisher
+	metrics          *metrics.Metrics
+	operations       *sync.WaitGroup
+	operationsMux    *sync.Mutex
+	closing          bool
+	userTokens       *keycloak.UserTokens
 }
 
-func New(config config.Config, db Database, deploymentClient deploy.DeploymentClient, kafkaAdmin KafkaAdmin, perm permV2Client.Client) *Controller {
+func New(config config.Config, db Database, deploymentClient deploy.DeploymentClient, kafkaAdmin KafkaAdmin, perm permV2Client.Client, events []EventPublisher, userTokens *keycloak.UserTokens, m *metrics.Metrics) *Controller {
 	return &Controller{
 		db:               db,
 		deploymentClient: deploymentClient,
 		kafkaAdmin:       kafkaAdmin,
 		config:           config,
 		permv2:           perm,
+		events:           events,
+		metrics:          m,
+		operations:       &sync.WaitGroup{},
+		operationsMux:    &sync.Mutex{},
+		userTokens:       userTokens,
 	}
 }
+
+var ErrShuttingDown = errors.New("service is shutting down")
+
+// startOperation registers a running operation, which is awaited by WaitForOperations.
+// The returned context keeps the values of ctx (e.g. the trace) but is not canceled with ctx,
+// to prevent canceled requests from leaving half deployed instances.
+func (this *Controller) startOperation(ctx context.Context) (operationCtx context.Context, done func(), err error) {
+	this.operationsMux.Lock()
+	defer this.operationsMux.Unlock()
+	if this.closing {
+		return ctx, func() {}, ErrShuttingDown
+	}
+	this.operations.Add(1)
+	return context.WithoutCancel(ctx), this.operations.Done, nil
+}
+
+// WaitForOperations rejects new operations and blocks until all running operations are done
+func (this *Controller) WaitForOperations() {
+	this.operationsMux.Lock()
+	this.closing = true
+	this.operationsMux.Unlock()
+	this.operations.Wait()
+}
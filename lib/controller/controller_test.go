@@ -0,0 +1,350 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+const testImportTypeId = "type1"
+
+// testEnv holds a controller, whose dependencies are kept in memory, and gives the tests access to them
+type testEnv struct {
+	controller  *Controller
+	db          *fakeDatabase
+	deploy      *fakeDeploy
+	kafka       *fakeKafka
+	events      *fakePublisher
+	perm        permV2Client.Client
+	importTypes map[string]model.ImportType
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	perm, err := permV2Client.NewTestClient(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range []string{model.PermV2InstanceTopic, "import-types"} {
+		_, err, _ = perm.SetTopic(permV2Client.InternalAdminToken, permV2Client.Topic{Id: topic})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	env := &testEnv{
+		db:     &fakeDatabase{perm: perm, instances: map[string]model.Instance{}},
+		deploy: &fakeDeploy{containers: map[string]string{}},
+		kafka:  &fakeKafka{topics: map[string]bool{}},
+		events: &fakePublisher{},
+		perm:   perm,
+		importTypes: map[string]model.ImportType{testImportTypeId: {
+			Id:             testImportTypeId,
+			Image:          "image1",
+			DefaultRestart: true,
+			Configs:        []model.ImportTypeConfig{{Name: "url", Type: model.String, DefaultValue: "http://default"}},
+		}},
+	}
+	repo := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		importType, ok := env.importTypes[strings.TrimPrefix(request.URL.Path, "/import-types/")]
+		if !ok {
+			http.Error(writer, "not found", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(writer).Encode(importType)
+	}))
+	t.Cleanup(repo.Close)
+	conf := config.Config{ImportRepoUrl: repo.URL}
+	env.controller = New(conf, env.db, env.deploy, env.kafka, perm, []EventPublisher{env.events}, nil, metrics.New())
+	return env
+}
+
+// allowImportType grants the user all rights, including execute, on the test import type
+func (this *testEnv) allowImportType(t *testing.T, userId string) {
+	_, err, _ := this.perm.SetPermission(permV2Client.InternalAdminToken, "import-types", testImportTypeId, permV2Client.ResourcePermissions{
+		UserPermissions: map[string]permV2Client.PermissionsMap{userId: {Read: true, Write: true, Execute: true, Administrate: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// create creates an instance of the test import type as the user
+func (this *testEnv) create(t *testing.T, userId string, instance model.Instance) model.Instance {
+	this.allowImportType(t, userId)
+	instance.ImportTypeId = testImportTypeId
+	result, err, _ := this.controller.CreateInstance(context.Background(), instance, testToken(userId), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// testToken returns an unsigned token, the fakes do not validate tokens
+func testToken(userId string) jwt.Token {
+	encode := func(value any) string {
+		b, _ := json.Marshal(value)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	raw := encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." +
+		encode(map[string]any{"sub": userId, "realm_access": map[string][]string{"roles": {"user"}}}) + ".c2lnbmF0dXJl"
+	token, err := jwt.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// fakeDatabase keeps instances in memory. Reads check the permissions like the mongo implementation,
+// transactions restore the instances on rollback. Methods not needed by the tests are not implemented and panic.
+type fakeDatabase struct {
+	Database
+	perm      permV2Client.Client
+	mux       sync.Mutex
+	instances map[string]model.Instance
+}
+
+func (this *fakeDatabase) Transaction(ctx context.Context) (context.Context, func(success bool) error, error) {
+	this.mux.Lock()
+	snapshot := maps.Clone(this.instances)
+	this.mux.Unlock()
+	return ctx, func(success bool) error {
+		if !success {
+			this.mux.Lock()
+			this.instances = snapshot
+			this.mux.Unlock()
+		}
+		return nil
+	}, nil
+}
+
+func (this *fakeDatabase) get(id string) (instance model.Instance, ok bool) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok = this.instances[id]
+	return instance, ok
+}
+
+func (this *fakeDatabase) GetInstance(_ context.Context, id string, jwt jwt.Token) (model.Instance, bool, error) {
+	ok, err, _ := this.perm.CheckPermission(jwt.Token, model.PermV2InstanceTopic, id, permV2Client.Read)
+	if err != nil {
+		return model.Instance{}, false, err
+	}
+	instance, exists := this.get(id)
+	if !ok || !exists {
+		return model.Instance{}, false, errors.New("requested instance nonexistent")
+	}
+	return instance, true, nil
+}
+
+func (this *fakeDatabase) CreateInstance(_ context.Context, instance model.Instance, jwt jwt.Token) error {
+	_, err, _ := this.perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permV2Client.ResourcePermissions{
+		UserPermissions: map[string]permV2Client.PermissionsMap{jwt.GetUserId(): {Read: true, Write: true, Execute: true, Administrate: true}},
+	})
+	if err != nil {
+		return err
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.instances[instance.Id] = instance
+	return nil
+}
+
+func (this *fakeDatabase) SetInstance(_ context.Context, instance model.Instance, _ jwt.Token, previousVersion int64) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	existing, ok := this.instances[instance.Id]
+	if !ok || existing.Version != previousVersion {
+		return model.ErrVersionConflict
+	}
+	this.instances[instance.Id] = instance
+	return nil
+}
+
+func (this *fakeDatabase) SetInstanceVersion(_ context.Context, id string, previousVersion int64, version int64) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok := this.instances[id]
+	if !ok || instance.Version != previousVersion {
+		return model.ErrVersionConflict
+	}
+	instance.Version = version
+	this.instances[id] = instance
+	return nil
+}
+
+// TransferInstance moves the permissions of the owner within the transaction, like the mongo implementation
+func (this *fakeDatabase) TransferInstance(_ context.Context, instance model.Instance, newOwner string, previousVersion int64) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	stored, ok := this.instances[instance.Id]
+	if !ok || stored.Version != previousVersion {
+		return model.ErrVersionConflict
+	}
+	_, err, _ := this.perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permV2Client.ResourcePermissions{
+		UserPermissions: map[string]permV2Client.PermissionsMap{newOwner: {Read: true, Write: true, Execute: true, Administrate: true}},
+	})
+	if err != nil {
+		return err
+	}
+	stored.Owner = newOwner
+	stored.Version = previousVersion + 1
+	this.instances[instance.Id] = stored
+	return nil
+}
+
+func (this *fakeDatabase) SetInstanceServiceId(_ context.Context, id string, serviceId string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	instance, ok := this.instances[id]
+	if !ok {
+		return errors.New("requested instance nonexistent")
+	}
+	instance.ServiceId = serviceId
+	this.instances[id] = instance
+	return nil
+}
+
+// RemoveInstance removes the permissions before the instance, like the mongo implementation
+func (this *fakeDatabase) RemoveInstance(_ context.Context, id string, _ jwt.Token) error {
+	err, _ := this.perm.RemoveResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, id)
+	if err != nil {
+		return err
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	delete(this.instances, id)
+	return nil
+}
+
+// fakeDeploy keeps the user label of each container
+type fakeDeploy struct {
+	deploy.DeploymentClient
+	mux        sync.Mutex
+	containers map[string]string
+	count      int
+}
+
+func (this *fakeDeploy) CreateContainer(_ context.Context, name string, _ string, _ map[string]string, _ bool, userid string, _ string) (string, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.count++
+	id := name + "-" + string(rune('a'+this.count))
+	this.containers[id] = userid
+	return id, nil
+}
+
+func (this *fakeDeploy) SetContainerOwner(_ context.Context, id string, _ *bool, userid string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if _, ok := this.containers[id]; !ok {
+		return errors.New("container not found")
+	}
+	this.containers[id] = userid
+	return nil
+}
+
+func (this *fakeDeploy) owner(id string) string {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.containers[id]
+}
+
+func (this *fakeDeploy) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, _ bool) (string, error) {
+	err := this.RemoveContainer(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return this.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
+}
+
+func (this *fakeDeploy) RemoveContainer(_ context.Context, id string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	delete(this.containers, id)
+	return nil
+}
+
+func (this *fakeDeploy) exists(id string) bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	_, ok := this.containers[id]
+	return ok
+}
+
+type fakeKafka struct {
+	KafkaAdmin
+	mux    sync.Mutex
+	topics map[string]bool
+}
+
+func (this *fakeKafka) CreateTopic(_ context.Context, name string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.topics[name] = true
+	return nil
+}
+
+func (this *fakeKafka) DeleteTopic(_ context.Context, name string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	delete(this.topics, name)
+	return nil
+}
+
+func (this *fakeKafka) exists(name string) bool {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.topics[name]
+}
+
+// fakePublisher records events; while failing is set, publishing fails
+type fakePublisher struct {
+	mux     sync.Mutex
+	events  []model.InstanceEvent
+	failing bool
+}
+
+func (this *fakePublisher) Publish(_ context.Context, event model.InstanceEvent) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.failing {
+		return errors.New("outbox not available")
+	}
+	this.events = append(this.events, event)
+	return nil
+}
+
+func (this *fakePublisher) fail(failing bool) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.failing = failing
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Controller) GetInstanceDataStats(ctx context.Context, id string, jwt jwt.Token) (result model.DataStats, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.GetInstanceDataStats")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return result, err, http.StatusNotFound
+	}
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	stats, err := this.kafkaAdmin.GetDataStats(ctx, []string{instance.KafkaTopic})
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	result, ok := stats[instance.KafkaTopic]
+	if !ok {
+		return result, errors.New("kafka topic of instance not found"), http.StatusNotFound
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Controller) addDataStats(ctx context.Context, instances []model.Instance) error {
+	topics := []string{}
+	for _, instance := range instances {
+		topics = append(topics, instance.KafkaTopic)
+	}
+	stats, err := this.kafkaAdmin.GetDataStats(ctx, topics)
+	if err != nil {
+		return err
+	}
+	for idx, instance := range instances {
+		instanceStats, ok := stats[instance.KafkaTopic]
+		if ok {
+			instances[idx].DataStats = &instanceStats
+		}
+	}
+	return nil
+}
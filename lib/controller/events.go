@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/hashicorp/go-uuid"
+)
+
+// publishEvent is called within the transaction of the mutation, see transaction
+func (this *Controller) publishEvent(ctx context.Context, action model.InstanceEventAction, instance model.Instance, changedFields []string) error {
+	return this.publish(ctx, model.InstanceEvent{Action: action, ChangedFields: changedFields}, instance)
+}
+
+// publish completes the event with the common fields of the instance and publishes it
+func (this *Controller) publish(ctx context.Context, event model.InstanceEvent, instance model.Instance) error {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+	event.Id = id
+	event.Version = model.InstanceEventVersion
+	event.InstanceId = instance.Id
+	event.Owner = instance.Owner
+	event.ImportTypeId = instance.ImportTypeId
+	event.KafkaTopic = instance.KafkaTopic
+	event.Time = time.Now()
+	event.PerformedBy = performedBy(ctx)
+	for _, publisher := range this.events {
+		err = publisher.Publish(ctx, event)
+		if err != nil {
+			return fmt.Errorf("unable to publish instance event: %w", err)
+		}
+	}
+	return nil
+}
+
+// transaction runs f in a database transaction, which is committed if f succeeds.
+// Mutations store the instance and publish their events within one transaction, so that events are not lost.
+// Without transaction support (mongo_repl_set=false) the writes of f are not atomic.
+func (this *Controller) transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, finish, err := this.db.Transaction(ctx)
+	if err != nil {
+		return err
+	}
+	err = f(ctx)
+	if err != nil {
+		_ = finish(false)
+		return err
+	}
+	return finish(true)
+}
+
+// changedFields returns the sorted json names of all fields that differ between the instances
+func changedFields(before model.Instance, after model.Instance) (result []string) {
+	result = []string{}
+	beforeMap, err := toJsonMap(before)
+	if err != nil {
+		log.Println("WARNING: unable to compare instances", err)
+		return result
+	}
+	afterMap, err := toJsonMap(after)
+	if err != nil {
+		log.Println("WARNING: unable to compare instances", err)
+		return result
+	}
+	for key, value := range afterMap {
+		if key == "updated_at" || key == "version" {
+			continue
+		}
+		if !reflect.DeepEqual(value, beforeMap[key]) {
+			result = append(result, key)
+		}
+	}
+	for key := range beforeMap {
+		if _, ok := afterMap[key]; !ok {
+			result = append(result, key)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+func toJsonMap(instance model.Instance) (result map[string]interface{}, err error) {
+	instance.DataStats = nil
+	b, err := json.Marshal(instance)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(b, &result)
+	return result, err
+}
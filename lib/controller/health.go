@@ -0,0 +1,118 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+)
+
+// used for the deploy backend check, no container with this id is expected to exist
+const healthCheckContainerId = "import-deploy-health-check"
+
+// CheckReadiness runs all dependency checks concurrently. Each check is limited by config.HealthCheckTimeout.
+func (this *Controller) CheckReadiness(ctx context.Context) (result model.Health, ready bool) {
+	timeout, err := time.ParseDuration(this.config.HealthCheckTimeout)
+	if err != nil {
+		return model.Health{Status: model.HealthError}, false
+	}
+	checks := map[string]func(ctx context.Context) error{
+		"mongo":             this.db.Ping,
+		"kafka":             this.kafkaAdmin.Ping,
+		"permissions":       this.checkPermissions,
+		"import_repository": this.checkImportRepository,
+		"deploy_backend":    this.checkDeploymentClient,
+	}
+	result = model.Health{Status: model.HealthOk, Checks: map[string]model.HealthCheck{}}
+	if this.config.SkipKafkaAdmin {
+		delete(checks, "kafka")
+		result.Checks["kafka"] = model.HealthCheck{Status: model.HealthDisabled}
+	}
+	mux := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for name, check := range checks {
+		wg.Go(func() {
+			checkResult := runHealthCheck(ctx, timeout, check)
+			mux.Lock()
+			defer mux.Unlock()
+			result.Checks[name] = checkResult
+			if checkResult.Status == model.HealthError {
+				result.Status = model.HealthError
+			}
+		})
+	}
+	wg.Wait()
+	return result, result.Status == model.HealthOk
+}
+
+// runHealthCheck does not wait for checks ignoring the context, after the timeout is reached
+func runHealthCheck(ctx context.Context, timeout time.Duration, check func(ctx context.Context) error) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := model.HealthCheck{Status: model.HealthOk, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = model.HealthError
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (this *Controller) checkPermissions(_ context.Context) error {
+	_, err, _ := this.permv2.GetTopic(permV2Client.InternalAdminToken, model.PermV2InstanceTopic)
+	return err
+}
+
+func (this *Controller) checkImportRepository(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, this.config.ImportRepoUrl, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := importRepoClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 500 {
+		return errors.New("unexpected status code " + strconv.Itoa(resp.StatusCode))
+	}
+	return nil
+}
+
+func (this *Controller) checkDeploymentClient(ctx context.Context) error {
+	_, err := this.deploymentClient.ContainerExists(ctx, healthCheckContainerId, nil)
+	return err
+}
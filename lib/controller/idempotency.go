@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// idempotencyPendingTimeout releases keys of create requests that never finished, e.g. because the service was restarted
+const idempotencyPendingTimeout = 10 * time.Minute
+
+const maxIdempotencyKeyLength = 255
+
+// CreateInstance creates the instance. With an idempotencyKey, retries of the same request return the response of the first request
+// instead of creating another instance. The key is scoped to the user and remembered for IdempotencyKeyTtl.
+func (this *Controller) CreateInstance(ctx context.Context, instance model.Instance, jwt jwt.Token, idempotencyKey string) (result model.Instance, err error, code int) {
+	if idempotencyKey == "" {
+		return this.createInstance(ctx, instance, jwt)
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return result, errors.New("Idempotency-Key too long"), http.StatusBadRequest
+	}
+	ttl, err := time.ParseDuration(this.config.IdempotencyKeyTtl)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	request, err := json.Marshal(instance)
+	if err != nil {
+		return result, err, http.StatusBadRequest
+	}
+	hash := sha256.Sum256(request)
+	now := time.Now().Truncate(time.Millisecond) // precision of mongo, RemoveIdempotencyKey and CompleteIdempotencyKey match the creation time
+	key := model.IdempotencyKey{
+		Id:          jwt.GetUserId() + "/" + idempotencyKey,
+		RequestHash: hex.EncodeToString(hash[:]),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	existing, reserved, err := this.reserveIdempotencyKey(ctx, key)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	if !reserved {
+		if existing.RequestHash != key.RequestHash {
+			return result, errors.New("the Idempotency-Key was already used for a different request"), http.StatusUnprocessableEntity
+		}
+		if existing.InstanceId == "" {
+			return result, errors.New("a request with this Idempotency-Key is still in progress"), http.StatusConflict
+		}
+		err = json.Unmarshal([]byte(existing.Response), &result)
+		if err != nil {
+			return result, err, http.StatusInternalServerError
+		}
+		return result, nil, http.StatusOK
+	}
+
+	result, err, code = this.createInstance(ctx, instance, jwt)
+	timeoutCtx, _ := util.GetChildTimeoutContext(context.WithoutCancel(ctx))
+	if err != nil {
+		// failed requests may be retried with the same key
+		removeErr := this.db.RemoveIdempotencyKey(timeoutCtx, key)
+		if removeErr != nil {
+			log.Println("ERROR: unable to remove idempotency key", key.Id, removeErr)
+		}
+		return result, err, code
+	}
+	response, err := json.Marshal(result)
+	if err == nil {
+		err = this.db.CompleteIdempotencyKey(timeoutCtx, key, result.Id, string(response))
+	}
+	if err != nil {
+		// the instance exists, a retry with this key will be answered with 409 until the key is released
+		log.Println("ERROR: unable to store response of idempotency key", key.Id, err)
+	}
+	return result, nil, code
+}
+
+// reserveIdempotencyKey replaces expired and abandoned keys
+func (this *Controller) reserveIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (existing model.IdempotencyKey, reserved bool, err error) {
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	existing, reserved, err = this.db.ReserveIdempotencyKey(timeoutCtx, key)
+	if err != nil || reserved {
+		return existing, reserved, err
+	}
+	abandoned := existing.InstanceId == "" && time.Since(existing.CreatedAt) > idempotencyPendingTimeout
+	if !abandoned && time.Now().Before(existing.ExpiresAt) {
+		return existing, false, nil
+	}
+	err = this.db.RemoveIdempotencyKey(timeoutCtx, existing)
+	if err != nil {
+		return existing, false, err
+	}
+	return this.db.ReserveIdempotencyKey(timeoutCtx, key)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+type performedByKey struct{}
+
+// performedBy returns the id of the admin acting on behalf of the user of the request, or an empty string
+func performedBy(ctx context.Context) string {
+	admin, _ := ctx.Value(performedByKey{}).(string)
+	return admin
+}
+
+// ImpersonateUser returns a token of the user, which can be used by admins to act on behalf of the user.
+// The returned context records the admin as performer of the following actions.
+func (this *Controller) ImpersonateUser(ctx context.Context, token jwt.Token, userId string) (userCtx context.Context, result jwt.Token, err error, code int) {
+	if !token.IsAdmin() {
+		return ctx, result, errors.New("for_user is only allowed for admins"), http.StatusForbidden
+	}
+	if userId == token.GetUserId() {
+		return ctx, token, nil, http.StatusOK
+	}
+	if !this.userTokens.Enabled() {
+		return ctx, result, errors.New("for_user is not configured"), http.StatusNotImplemented
+	}
+	result, err = this.userTokens.Get(ctx, userId)
+	if err != nil {
+		return ctx, result, err, http.StatusBadGateway
+	}
+	log.Println("admin", token.GetUserId(), "acts on behalf of", userId)
+	return context.WithValue(ctx, performedByKey{}, token.GetUserId()), result, nil, http.StatusOK
+}
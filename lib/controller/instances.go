@@ -17,6 +17,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
@@ -26,36 +27,89 @@ import (
 	"time"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
 	"github.com/SENERGY-Platform/import-deploy/lib/util"
 	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"github.com/hashicorp/go-uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 const idPrefix = "urn:infai:ses:import:"
 const containerNamePrefix = "import-"
 
-func (this *Controller) ListInstances(jwt jwt.Token, limit int64, offset int64, sort string, asc bool, search string, includeGenerated bool) (results []model.Instance, err error, errCode int) {
-	ctx, _ := util.GetTimeoutContext()
-	results, err = this.db.ListInstances(ctx, limit, offset, sort, jwt, asc, search, includeGenerated)
+// importRepoClient propagates the trace context to the import-repository
+var importRepoClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+func (this *Controller) ListInstances(ctx context.Context, jwt jwt.Token, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor, includeDataStats bool) (results []model.Instance, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.ListInstances")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	results, err = this.db.ListInstances(timeoutCtx, limit, offset, sort, jwt, asc, filter, after)
 	if err != nil {
 		return results, err, http.StatusInternalServerError
 	}
+	if includeDataStats {
+		err = this.addDataStats(ctx, results)
+		if err != nil {
+			return results, err, http.StatusInternalServerError
+		}
+	}
 	return results, nil, http.StatusOK
 }
 
-func (this *Controller) CountInstances(jwt jwt.Token, search string, includeGenerated bool) (count int64, err error, errCode int) {
-	ctx, _ := util.GetTimeoutContext()
-	count, err = this.db.CountInstances(ctx, jwt, search, includeGenerated)
+func (this *Controller) CountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (count int64, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.CountInstances")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	count, err = this.db.CountInstances(timeoutCtx, jwt, filter)
 	if err != nil {
 		return count, err, http.StatusInternalServerError
 	}
 	return count, nil, http.StatusOK
 }
 
-func (this *Controller) ReadInstance(id string, jwt jwt.Token) (result model.Instance, err error, errCode int) {
-	ctx, _ := util.GetTimeoutContext()
-	result, exists, err := this.db.GetInstance(ctx, id, jwt)
+// AdminListInstances lists the instances of all users
+func (this *Controller) AdminListInstances(ctx context.Context, jwt jwt.Token, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor, includeDataStats bool) (results []model.Instance, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.AdminListInstances")
+	defer tracing.End(span, &err)
+	if !jwt.IsAdmin() {
+		return results, errors.New("only allowed for admins"), http.StatusForbidden
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	results, err = this.db.AdminListInstances(timeoutCtx, limit, offset, sort, asc, filter, after)
+	if err != nil {
+		return results, err, http.StatusInternalServerError
+	}
+	if includeDataStats {
+		err = this.addDataStats(ctx, results)
+		if err != nil {
+			return results, err, http.StatusInternalServerError
+		}
+	}
+	return results, nil, http.StatusOK
+}
+
+// AdminCountInstances counts the instances of all users
+func (this *Controller) AdminCountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (count int64, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.AdminCountInstances")
+	defer tracing.End(span, &err)
+	if !jwt.IsAdmin() {
+		return count, errors.New("only allowed for admins"), http.StatusForbidden
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	count, err = this.db.AdminCountInstances(timeoutCtx, filter)
+	if err != nil {
+		return count, err, http.StatusInternalServerError
+	}
+	return count, nil, http.StatusOK
+}
+
+func (this *Controller) ReadInstance(ctx context.Context, id string, jwt jwt.Token) (result model.Instance, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.ReadInstance")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	result, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
 	if !exists {
 		return result, err, http.StatusNotFound
 	}
@@ -65,7 +119,15 @@ func (this *Controller) ReadInstance(id string, jwt jwt.Token) (result model.Ins
 	return result, nil, http.StatusOK
 }
 
-func (this *Controller) CreateInstance(instance model.Instance, jwt jwt.Token) (result model.Instance, err error, code int) {
+func (this *Controller) createInstance(ctx context.Context, instance model.Instance, jwt jwt.Token) (result model.Instance, err error, code int) {
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return result, err, http.StatusServiceUnavailable
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.CreateInstance")
+	defer tracing.End(span, &err)
+	defer this.metrics.ObserveOperation("create", time.Now(), &err)
 	if instance.Id != "" {
 		return result, errors.New("explicit setting of id not allowed"), http.StatusBadRequest
 	}
@@ -78,12 +140,15 @@ func (this *Controller) CreateInstance(instance model.Instance, jwt jwt.Token) (
 	}
 	instance.Id = idPrefix + id
 	instance.Owner = jwt.GetUserId()
-	instance, err, code = this.fillDefaultValues(instance, jwt)
+	instance.Stale = false
+	instance.StaleSince = nil
+	instance.Runtime = nil
+	instance, err, code = this.fillDefaultValues(ctx, instance, jwt)
 	if err != nil || code != http.StatusOK {
 		return result, err, code
 	}
 
-	access, err := this.hasXAccess(jwt, instance.ImportTypeId)
+	access, err := this.hasXAccess(ctx, jwt, instance.ImportTypeId)
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
@@ -96,7 +161,7 @@ func (this *Controller) CreateInstance(instance model.Instance, jwt jwt.Token) (
 		return result, err, http.StatusBadRequest
 	}
 	if !this.config.SkipKafkaAdmin {
-		err = this.kafkaAdmin.CreateTopic(instance.KafkaTopic)
+		err = this.kafkaAdmin.CreateTopic(ctx, instance.KafkaTopic)
 		if err != nil {
 			return result, err, http.StatusInternalServerError
 		}
@@ -107,40 +172,82 @@ func (this *Controller) CreateInstance(instance model.Instance, jwt jwt.Token) (
 	} else {
 		restart = false
 	}
-	instance.ServiceId, err = this.deploymentClient.CreateContainer(containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId)
+	instance.ServiceId, err = this.deploymentClient.CreateContainer(ctx, containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId)
 	if err != nil {
+		this.undoCreation(ctx, instance, false)
 		return result, err, http.StatusInternalServerError
 	}
 
 	now := time.Now()
 	instance.CreatedAt = now
 	instance.UpdatedAt = now
-	ctx, _ := util.GetTimeoutContext()
-	err = this.db.CreateInstance(ctx, instance, jwt)
+	err = this.transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.CreateInstance(timeoutCtx, instance, jwt)
+		if err != nil {
+			return err
+		}
+		return this.publishEvent(ctx, model.InstanceCreated, instance, nil)
+	})
 	if err != nil {
+		this.undoCreation(ctx, instance, true)
 		return result, err, http.StatusInternalServerError
 	}
 	return instance, nil, http.StatusOK
 }
 
-func (this *Controller) SetInstance(instance model.Instance, jwt jwt.Token) (err error, code int) {
-	ctx, _ := util.GetTimeoutContext()
-	existing, exists, err := this.db.GetInstance(ctx, instance.Id, jwt)
+// undoCreation removes the topic, the container (if deployed) and the permissions of an instance, which could not be stored
+func (this *Controller) undoCreation(ctx context.Context, instance model.Instance, deployed bool) {
+	if deployed {
+		err := this.deploymentClient.RemoveContainer(ctx, instance.ServiceId)
+		if err != nil {
+			log.Println("ERROR: unable to remove container of failed creation", instance.Id, err)
+		}
+	}
+	if !this.config.SkipKafkaAdmin {
+		err := this.kafkaAdmin.DeleteTopic(ctx, instance.KafkaTopic)
+		if err != nil {
+			log.Println("ERROR: unable to delete topic of failed creation", instance.Id, err)
+		}
+	}
+	err, code := this.permv2.RemoveResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id)
+	if err != nil && code != http.StatusNotFound {
+		log.Println("ERROR: unable to remove permissions of failed creation", instance.Id, err)
+	}
+}
+
+// SetInstance updates the instance and its container. With expectedVersion, the update is only applied to that version of the instance.
+// Concurrent updates are rejected with 412 before the container is touched.
+func (this *Controller) SetInstance(ctx context.Context, instance model.Instance, jwt jwt.Token, expectedVersion *int64) (err error, code int) {
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return err, http.StatusServiceUnavailable
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.SetInstance")
+	defer tracing.End(span, &err)
+	defer this.metrics.ObserveOperation("update", time.Now(), &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	existing, exists, err := this.db.GetInstance(timeoutCtx, instance.Id, jwt)
 	if !exists {
 		return errors.New("not found"), http.StatusNotFound
 	}
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		return model.ErrVersionConflict, http.StatusPreconditionFailed
+	}
 	if existing.ImportTypeId != instance.ImportTypeId {
 		return errors.New("change of import type not supported"), http.StatusBadRequest
 	}
-	instance, err, code = this.fillDefaultValues(instance, jwt)
+	instance.Owner = existing.Owner
+	instance, err, code = this.fillDefaultValues(ctx, instance, jwt)
 	if err != nil || code != http.StatusOK {
 		return err, code
 	}
 
-	access, err := this.hasXAccess(jwt, instance.ImportTypeId)
+	access, err := this.hasXAccess(ctx, jwt, instance.ImportTypeId)
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
@@ -166,62 +273,189 @@ func (this *Controller) SetInstance(instance model.Instance, jwt jwt.Token) (err
 		existingRestart = false
 	}
 
-	instance.ServiceId, err = this.deploymentClient.UpdateContainer(existing.ServiceId, containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId, existingRestart)
+	// claiming the next version first lets concurrent updates fail before the container is touched
+	err, code = this.checkPermission(ctx, instance.Id, jwt, permV2Client.Write)
+	if err != nil {
+		return err, code
+	}
+	claimedVersion := existing.Version + 1
+	timeoutCtx, _ = util.GetChildTimeoutContext(ctx)
+	err = this.db.SetInstanceVersion(timeoutCtx, instance.Id, existing.Version, claimedVersion)
+	if errors.Is(err, model.ErrVersionConflict) {
+		return err, http.StatusPreconditionFailed
+	}
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
+
+	serviceId, err := this.deploymentClient.UpdateContainer(ctx, existing.ServiceId, containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId, existingRestart)
+	if err != nil {
+		// the deployment is unchanged
+		this.releaseVersion(ctx, instance.Id, claimedVersion, existing.Version)
+		return err, http.StatusInternalServerError
+	}
+	instance.ServiceId = serviceId
 	instance.UpdatedAt = time.Now()
-	ctx, _ = util.GetTimeoutContext()
-	err = this.db.SetInstance(ctx, instance, jwt)
+	instance.Stale = existing.Stale
+	instance.StaleSince = existing.StaleSince
+	instance.Runtime = nil // observed again for the new container
+	instance.Version = claimedVersion + 1
+	err = this.transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.SetInstance(timeoutCtx, instance, jwt, claimedVersion)
+		if err != nil {
+			return err
+		}
+		return this.publishEvent(ctx, model.InstanceUpdated, instance, changedFields(existing, instance))
+	})
+	if errors.Is(err, model.ErrVersionConflict) {
+		return err, http.StatusPreconditionFailed
+	}
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
 	return nil, http.StatusOK
 }
 
-func (this *Controller) DeleteInstance(id string, jwt jwt.Token) (err error, errCode int) {
-	ctx, _ := util.GetTimeoutContext()
-	instance, exists, err := this.db.GetInstance(ctx, id, jwt)
+// releaseVersion resets the version claimed by a failed operation
+func (this *Controller) releaseVersion(ctx context.Context, id string, claimedVersion int64, previousVersion int64) {
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err := this.db.SetInstanceVersion(timeoutCtx, id, claimedVersion, previousVersion)
+	if err != nil {
+		log.Println("ERROR: unable to release version of instance after failed operation", id, err)
+	}
+}
+
+// DeleteInstance removes the instance, its container and topic. With expectedVersion, only that version of the instance is deleted.
+func (this *Controller) DeleteInstance(ctx context.Context, id string, jwt jwt.Token, expectedVersion *int64) (err error, errCode int) {
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return err, http.StatusServiceUnavailable
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.DeleteInstance")
+	defer tracing.End(span, &err)
+	defer this.metrics.ObserveOperation("delete", time.Now(), &err)
+	err, errCode = this.checkAdministrate(ctx, id, jwt)
+	if err != nil {
+		return err, errCode
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
 	if !exists {
 		return errors.New("not found"), http.StatusNotFound
 	}
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
-	err = this.deploymentClient.RemoveContainer(instance.ServiceId)
+	if expectedVersion != nil && *expectedVersion != instance.Version {
+		return model.ErrVersionConflict, http.StatusPreconditionFailed
+	}
+	// claim the instance, so that concurrent updates fail before touching the container.
+	// Only the version is changed, which does not require the write right.
+	claimedVersion := instance.Version + 1
+	timeoutCtx, _ = util.GetChildTimeoutContext(ctx)
+	err = this.db.SetInstanceVersion(timeoutCtx, id, instance.Version, claimedVersion)
+	if errors.Is(err, model.ErrVersionConflict) {
+		return err, http.StatusPreconditionFailed
+	}
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	// the permissions are removed with the instance and restored, if the removal fails
+	permissions, err, _ := this.permv2.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, id)
 	if err != nil {
+		this.releaseVersion(ctx, id, claimedVersion, instance.Version)
+		return err, http.StatusInternalServerError
+	}
+	err = this.deploymentClient.RemoveContainer(ctx, instance.ServiceId)
+	if err != nil {
+		this.releaseVersion(ctx, id, claimedVersion, instance.Version)
 		return err, http.StatusInternalServerError
 	}
 
 	if !this.config.SkipKafkaAdmin {
-		err = this.kafkaAdmin.DeleteTopic(instance.KafkaTopic)
+		err = this.kafkaAdmin.DeleteTopic(ctx, instance.KafkaTopic)
 		if err != nil {
+			this.redeploy(ctx, instance)
+			this.releaseVersion(ctx, id, claimedVersion, instance.Version)
 			return err, http.StatusInternalServerError
 		}
 	}
+	removed := instance
+	removed.Version = claimedVersion
 
-	err = this.db.RemoveInstance(ctx, id, jwt)
+	err = this.transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.RemoveInstance(timeoutCtx, id, jwt)
+		if err != nil {
+			return err
+		}
+		return this.publishEvent(ctx, model.InstanceDeleted, removed, nil)
+	})
 	if err != nil {
+		this.undoRemoval(ctx, instance, permissions.ResourcePermissions)
+		this.releaseVersion(ctx, id, claimedVersion, instance.Version)
 		return err, http.StatusInternalServerError
 	}
 	return nil, http.StatusNoContent
 }
 
-func (this *Controller) EnsureAllInstancesDeployed() (err error) {
-	var offset int64 = 0
+// undoRemoval restores the permissions, the topic and the container of an instance, which could not be removed from the database
+func (this *Controller) undoRemoval(ctx context.Context, instance model.Instance, permissions permV2Client.ResourcePermissions) {
+	_, err, _ := this.permv2.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permissions)
+	if err != nil {
+		log.Println("ERROR: unable to restore permissions of failed removal", instance.Id, err)
+	}
+	if !this.config.SkipKafkaAdmin {
+		err = this.kafkaAdmin.CreateTopic(ctx, instance.KafkaTopic)
+		if err != nil {
+			log.Println("ERROR: unable to recreate topic of failed removal", instance.Id, err)
+		}
+	}
+	this.redeploy(ctx, instance)
+}
+
+// redeploy recreates the removed container of an instance after a failed removal; on failure it is recreated by
+// EnsureAllInstancesDeployed on the next start
+func (this *Controller) redeploy(ctx context.Context, instance model.Instance) {
+	err := this.recreateContainer(ctx, instance)
+	if err != nil {
+		log.Println("ERROR: unable to recreate container of failed removal", instance.Id, err)
+	}
+}
+
+// EnsureAllInstancesDeployed recreates missing containers. Cancellation of ctx stops the reconciliation between instances.
+func (this *Controller) EnsureAllInstancesDeployed(ctx context.Context) (err error) {
+	defer this.metrics.ObserveOperation("ensure", time.Now(), &err)
+	stop := ctx.Done()
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.EnsureAllInstancesDeployed")
+	defer tracing.End(span, &err)
+	var after *model.InstanceCursor
 	var batchSize int64 = 100
 	for {
-		ctx, _ := util.GetTimeoutContext()
-		instances, err := this.db.ListInstances(ctx, batchSize, offset, "name", jwt.Token{Token: permV2Client.InternalAdminToken}, true, "", true)
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		instances, err := this.db.AdminListInstances(timeoutCtx, batchSize, 0, "id", true, model.InstanceFilter{}, after)
 		if err != nil {
 			return err
 		}
 		if len(instances) == 0 {
 			return nil // done
 		}
-		offset += int64(len(instances))
+		cursor := model.NewInstanceCursor("id", true, instances[len(instances)-1])
+		after = &cursor
 		for _, instance := range instances {
-			exists, err := this.deploymentClient.ContainerExists(instance.ServiceId, instance.Restart)
+			select {
+			case <-stop:
+				return context.Canceled
+			default:
+			}
+			exists, err := this.deploymentClient.ContainerExists(ctx, instance.ServiceId, instance.Restart)
 			if err != nil {
 				return err
 			}
@@ -230,22 +464,7 @@ func (this *Controller) EnsureAllInstancesDeployed() (err error) {
 				continue
 			}
 			log.Println("Recreating " + instance.Id)
-			env, err := this.getEnv(instance)
-			if err != nil {
-				return err
-			}
-			var restart bool
-			if instance.Restart == nil || *instance.Restart {
-				restart = true
-			} else {
-				restart = false
-			}
-			instance.ServiceId, err = this.deploymentClient.CreateContainer(containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId)
-			if err != nil {
-				return err
-			}
-			ctx, _ := util.GetTimeoutContext()
-			err = this.db.SetInstance(ctx, instance, jwt.Token{Token: permV2Client.InternalAdminToken})
+			err = this.recreateContainer(ctx, instance)
 			if err != nil {
 				return err
 			}
@@ -253,8 +472,28 @@ func (this *Controller) EnsureAllInstancesDeployed() (err error) {
 	}
 }
 
-func (this *Controller) fillDefaultValues(instance model.Instance, jwt jwt.Token) (result model.Instance, err error, code int) {
-	importType, err, code := this.getImportType(instance.ImportTypeId, jwt)
+// recreateContainer deploys a new container for the stored instance
+func (this *Controller) recreateContainer(ctx context.Context, instance model.Instance) (err error) {
+	env, err := this.getEnv(instance)
+	if err != nil {
+		return err
+	}
+	var restart bool
+	if instance.Restart == nil || *instance.Restart {
+		restart = true
+	} else {
+		restart = false
+	}
+	instance.ServiceId, err = this.deploymentClient.CreateContainer(ctx, containerNamePrefix+strings.TrimPrefix(instance.Id, idPrefix), instance.Image, env, restart, instance.Owner, instance.ImportTypeId)
+	if err != nil {
+		return err
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	return this.db.SetInstanceServiceId(timeoutCtx, instance.Id, instance.ServiceId)
+}
+
+func (this *Controller) fillDefaultValues(ctx context.Context, instance model.Instance, jwt jwt.Token) (result model.Instance, err error, code int) {
+	importType, err, code := this.getImportType(ctx, instance.ImportTypeId, jwt)
 	if err != nil {
 		return instance, err, code
 	}
@@ -279,14 +518,28 @@ func (this *Controller) fillDefaultValues(instance model.Instance, jwt jwt.Token
 	if instance.Restart == nil {
 		instance.Restart = &importType.DefaultRestart
 	}
+	if instance.ExpectedInterval == "" {
+		instance.ExpectedInterval = importType.DefaultExpectedInterval
+	}
+	if instance.ExpectedInterval != "" {
+		_, err = time.ParseDuration(instance.ExpectedInterval)
+		if err != nil {
+			return instance, errors.New("invalid expected_interval: " + err.Error()), http.StatusBadRequest
+		}
+	}
+	for key := range instance.Labels {
+		if !model.ValidLabelKey(key) {
+			return instance, errors.New("invalid label key " + key), http.StatusBadRequest
+		}
+	}
 	instance.KafkaTopic = strings.ReplaceAll(instance.Id, ":", "_")
 	return instance, nil, http.StatusOK
 }
 
-func (this *Controller) getImportType(id string, jwt jwt.Token) (importType model.ImportType, err error, code int) {
-	req, err := http.NewRequest("GET", this.config.ImportRepoUrl+"/import-types/"+id, nil)
+func (this *Controller) getImportType(ctx context.Context, id string, jwt jwt.Token) (importType model.ImportType, err error, code int) {
+	req, err := http.NewRequestWithContext(ctx, "GET", this.config.ImportRepoUrl+"/import-types/"+id, nil)
 	req.Header.Set("Authorization", jwt.Token)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := importRepoClient.Do(req)
 
 	if err != nil {
 		return importType, errors.New("unable to contact import repo"), http.StatusBadGateway
@@ -367,7 +620,9 @@ func (this *Controller) getEnv(instance model.Instance) (m map[string]string, er
 	return m, nil
 }
 
-func (this *Controller) hasXAccess(jwt jwt.Token, importTypeId string) (bool, error) {
+func (this *Controller) hasXAccess(ctx context.Context, jwt jwt.Token, importTypeId string) (bool, error) {
+	_, span := tracing.Start(ctx, "permissions.CheckPermission")
 	access, err, _ := this.permv2.CheckPermission(jwt.Token, "import-types", importTypeId, 'x')
+	tracing.End(span, &err)
 	return access, err
 }
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+)
+
+func TestCreateIsUndoneWhenPublishFails(t *testing.T) {
+	env := newTestEnv(t)
+	env.allowImportType(t, "user1")
+	env.events.fail(true)
+
+	_, err, code := env.controller.CreateInstance(context.Background(), model.Instance{Name: "test", ImportTypeId: testImportTypeId}, testToken("user1"), "")
+	if err == nil || code != http.StatusInternalServerError {
+		t.Fatal(code, err)
+	}
+	if len(env.db.instances) != 0 {
+		t.Error("instance not rolled back", env.db.instances)
+	}
+	if len(env.deploy.containers) != 0 {
+		t.Error("container not removed", env.deploy.containers)
+	}
+	if len(env.kafka.topics) != 0 {
+		t.Error("topic not deleted", env.kafka.topics)
+	}
+	ids, err, _ := env.perm.ListAccessibleResourceIds(testToken("user1").Token, model.PermV2InstanceTopic, permV2Client.ListOptions{}, permV2Client.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Error("permissions not removed", ids)
+	}
+}
+
+func TestDeleteIsUndoneWhenPublishFails(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.create(t, "user1", model.Instance{Name: "test"})
+	env.events.fail(true)
+
+	err, code := env.controller.DeleteInstance(context.Background(), created.Id, testToken("user1"), nil)
+	if err == nil || code != http.StatusInternalServerError {
+		t.Fatal(code, err)
+	}
+	stored, err, _ := env.controller.ReadInstance(context.Background(), created.Id, testToken("user1"))
+	if err != nil {
+		t.Fatal("instance not readable after failed removal", err)
+	}
+	if stored.Version != created.Version {
+		t.Error("claimed version not released", stored.Version, created.Version)
+	}
+	if !env.deploy.exists(stored.ServiceId) {
+		t.Error("container not recreated", stored.ServiceId, env.deploy.containers)
+	}
+	if !env.kafka.exists(stored.KafkaTopic) {
+		t.Error("topic not recreated")
+	}
+
+	// the user can retry the deletion
+	env.events.fail(false)
+	err, code = env.controller.DeleteInstance(context.Background(), created.Id, testToken("user1"), &created.Version)
+	if err != nil {
+		t.Fatal(code, err)
+	}
+	if _, ok := env.db.get(created.Id); ok {
+		t.Error("instance not removed")
+	}
+	if len(env.deploy.containers) != 0 || len(env.kafka.topics) != 0 {
+		t.Error(env.deploy.containers, env.kafka.topics)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Controller) GetInstanceLogs(ctx context.Context, id string, jwt jwt.Token, tail int64) (logs string, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.GetInstanceLogs")
+	defer tracing.End(span, &err)
+	if tail < 1 {
+		return logs, errors.New("tail must be positive"), http.StatusBadRequest
+	}
+	if this.config.LogsMaxLines > 0 && tail > this.config.LogsMaxLines {
+		tail = this.config.LogsMaxLines
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return logs, err, http.StatusNotFound
+	}
+	if err != nil {
+		return logs, err, http.StatusInternalServerError
+	}
+	logs, err = this.deploymentClient.ContainerLogs(ctx, instance.ServiceId, instance.Restart, tail)
+	if errors.Is(err, deploy.ErrNotSupported) {
+		return logs, err, http.StatusNotImplemented
+	}
+	if errors.Is(err, deploy.ErrNotFound) {
+		return logs, err, http.StatusNotFound
+	}
+	if err != nil {
+		return logs, err, http.StatusBadGateway
+	}
+	return logs, nil, http.StatusOK
+}
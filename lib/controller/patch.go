@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+const JsonPatchContentType = "application/json-patch+json"
+
+// patchableInstance is the document patches are applied to. Configs are addressed by name, e.g. /configs/url in a JSON Patch.
+type patchableInstance struct {
+	Name    string                 `json:"name"`
+	Configs map[string]interface{} `json:"configs"`
+	Restart *bool                  `json:"restart"`
+	Labels  map[string]string      `json:"labels"`
+}
+
+// PatchInstance applies a JSON Merge Patch or JSON Patch (selected by contentType) to name, configs, restart and labels
+// of the stored instance and updates it with SetInstance. Removed configs are reset to the default of the import type.
+func (this *Controller) PatchInstance(ctx context.Context, id string, contentType string, patch []byte, jwt jwt.Token, expectedVersion *int64) (result model.Instance, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.PatchInstance")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	existing, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return result, errors.New("not found"), http.StatusNotFound
+	}
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		return result, model.ErrVersionConflict, http.StatusPreconditionFailed
+	}
+
+	patchable := patchableInstance{Name: existing.Name, Configs: map[string]interface{}{}, Restart: existing.Restart, Labels: existing.Labels}
+	for _, config := range existing.Configs {
+		patchable.Configs[config.Name] = config.Value
+	}
+	doc, err := json.Marshal(patchable)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	switch contentType {
+	case MergePatchContentType:
+		doc, err = jsonpatch.MergePatch(doc, patch)
+	case JsonPatchContentType:
+		var operations jsonpatch.Patch
+		operations, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			doc, err = operations.Apply(doc)
+		}
+	default:
+		return result, errors.New("unsupported content type, use " + MergePatchContentType + " or " + JsonPatchContentType), http.StatusUnsupportedMediaType
+	}
+	if err != nil {
+		return result, errors.New("unable to apply patch: " + err.Error()), http.StatusBadRequest
+	}
+	patched := patchableInstance{}
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&patched)
+	if err != nil {
+		return result, errors.New("only name, configs, restart and labels can be patched: " + err.Error()), http.StatusBadRequest
+	}
+
+	instance := existing
+	instance.Name = patched.Name
+	instance.Restart = patched.Restart
+	instance.Labels = patched.Labels
+	importType, err, code := this.getImportType(ctx, existing.ImportTypeId, jwt)
+	if err != nil {
+		return result, err, code
+	}
+	// configs are validated against the import type, which may define configs the instance does not store yet.
+	// Stored configs the import type no longer defines are kept as long as they are not changed.
+	for name, value := range patched.Configs {
+		if slices.ContainsFunc(importType.Configs, func(config model.ImportTypeConfig) bool { return config.Name == name }) {
+			continue
+		}
+		idx, ok := indexOf(existing.Configs, name)
+		if !ok || !sameJson(existing.Configs[idx].Value, value) {
+			return result, errors.New("unknown config " + name), http.StatusBadRequest
+		}
+	}
+	instance.Configs = []model.InstanceConfig{}
+	for _, config := range existing.Configs {
+		value, ok := patched.Configs[config.Name]
+		if ok {
+			instance.Configs = append(instance.Configs, model.InstanceConfig{Name: config.Name, Value: value})
+		}
+	}
+	for _, config := range importType.Configs {
+		value, ok := patched.Configs[config.Name]
+		if _, stored := indexOf(existing.Configs, config.Name); ok && !stored {
+			instance.Configs = append(instance.Configs, model.InstanceConfig{Name: config.Name, Value: value})
+		}
+	}
+	err, code = this.SetInstance(ctx, instance, jwt, &existing.Version)
+	if err != nil {
+		return result, err, code
+	}
+	return this.ReadInstance(ctx, id, jwt)
+}
+
+func sameJson(a interface{}, b interface{}) bool {
+	aJson, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJson, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aJson, bJson)
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+func TestPatchConfigOfImportType(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.create(t, "user1", model.Instance{Name: "test"})
+
+	// configs added to the import type after the creation are not stored by the instance
+	importType := env.importTypes[testImportTypeId]
+	importType.Configs = append(importType.Configs, model.ImportTypeConfig{Name: "interval", Type: model.Integer, DefaultValue: float64(10)})
+	env.importTypes[testImportTypeId] = importType
+
+	patched, err, code := env.controller.PatchInstance(context.Background(), created.Id, MergePatchContentType, []byte(`{"configs": {"interval": 20}}`), testToken("user1"), nil)
+	if err != nil {
+		t.Fatal(code, err)
+	}
+	values := map[string]interface{}{}
+	for _, config := range patched.Configs {
+		values[config.Name] = config.Value
+	}
+	if values["interval"] != float64(20) || values["url"] != "http://default" {
+		t.Error(values)
+	}
+
+	_, _, code = env.controller.PatchInstance(context.Background(), created.Id, MergePatchContentType, []byte(`{"configs": {"interval": "often"}}`), testToken("user1"), nil)
+	if code != http.StatusBadRequest {
+		t.Error("invalid value accepted", code)
+	}
+	_, _, code = env.controller.PatchInstance(context.Background(), created.Id, MergePatchContentType, []byte(`{"configs": {"unknown": 1}}`), testToken("user1"), nil)
+	if code != http.StatusBadRequest {
+		t.Error("unknown config accepted", code)
+	}
+}
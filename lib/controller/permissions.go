@@ -0,0 +1,109 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Controller) GetInstancePermissions(ctx context.Context, id string, jwt jwt.Token) (result permV2Client.ResourcePermissions, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.GetInstancePermissions")
+	defer tracing.End(span, &err)
+	err, code = this.checkAdministrate(ctx, id, jwt)
+	if err != nil {
+		return result, err, code
+	}
+	_, permSpan := tracing.Start(ctx, "permissions.GetResource")
+	resource, err, code := this.permv2.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, id)
+	tracing.End(permSpan, &err)
+	if err != nil {
+		return result, err, code
+	}
+	return resource.ResourcePermissions, nil, http.StatusOK
+}
+
+// SetInstancePermissions replaces the user, group and role permissions of the instance. The owner has to keep all rights.
+func (this *Controller) SetInstancePermissions(ctx context.Context, id string, permissions permV2Client.ResourcePermissions, jwt jwt.Token) (result permV2Client.ResourcePermissions, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.SetInstancePermissions")
+	defer tracing.End(span, &err)
+	err, code = this.checkAdministrate(ctx, id, jwt)
+	if err != nil {
+		return result, err, code
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return result, errors.New("not found"), http.StatusNotFound
+	}
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	if permissions.UserPermissions == nil {
+		permissions.UserPermissions = map[string]permV2Client.PermissionsMap{}
+	}
+	if permissions.GroupPermissions == nil {
+		permissions.GroupPermissions = map[string]permV2Client.PermissionsMap{}
+	}
+	if permissions.RolePermissions == nil {
+		permissions.RolePermissions = map[string]permV2Client.PermissionsMap{}
+	}
+	owner := permissions.UserPermissions[instance.Owner]
+	if !owner.Read || !owner.Write || !owner.Execute || !owner.Administrate {
+		return result, errors.New("the owner has to keep all rights"), http.StatusBadRequest
+	}
+	result, err, code = this.db.SetInstancePermissions(ctx, id, permissions)
+	if err != nil {
+		return result, err, code
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Controller) checkAdministrate(ctx context.Context, id string, jwt jwt.Token) (err error, code int) {
+	return this.checkPermission(ctx, id, jwt, permV2Client.Administrate)
+}
+
+func (this *Controller) checkPermission(ctx context.Context, id string, jwt jwt.Token, permission permV2Client.Permission) (err error, code int) {
+	_, span := tracing.Start(ctx, "permissions.CheckPermission")
+	ok, err, _ := this.permv2.CheckPermission(jwt.Token, model.PermV2InstanceTopic, id, permission)
+	tracing.End(span, &err)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if !ok {
+		return errors.New("not found or missing " + permissionName(permission) + " right"), http.StatusNotFound
+	}
+	return nil, http.StatusOK
+}
+
+func permissionName(permission permV2Client.Permission) string {
+	switch permission {
+	case permV2Client.Read:
+		return "read"
+	case permV2Client.Write:
+		return "write"
+	case permV2Client.Execute:
+		return "execute"
+	}
+	return "administrate"
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	kafkaAdmin "github.com/SENERGY-Platform/import-deploy/lib/kafka-admin"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Controller) PreviewInstance(ctx context.Context, id string, jwt jwt.Token, n int64) (result []model.PreviewRecord, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.PreviewInstance")
+	defer tracing.End(span, &err)
+	if n < 1 {
+		return result, errors.New("n must be positive"), http.StatusBadRequest
+	}
+	if this.config.PreviewMaxRecords > 0 && n > this.config.PreviewMaxRecords {
+		n = this.config.PreviewMaxRecords
+	}
+	timeout, err := time.ParseDuration(this.config.PreviewTimeout)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return result, err, http.StatusNotFound
+	}
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	result, err = this.kafkaAdmin.ReadLatest(ctx, instance.KafkaTopic, n, this.config.PreviewMaxRecordBytes, timeout)
+	if errors.Is(err, kafkaAdmin.ErrTopicNotFound) {
+		return result, err, http.StatusNotFound
+	}
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	return result, nil, http.StatusOK
+}
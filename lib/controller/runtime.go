@@ -0,0 +1,134 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+)
+
+// StartRuntimeCheck periodically reads the state of all containers and publishes crash and completed events.
+// The check stops, if the deployment backend does not report container states.
+func (this *Controller) StartRuntimeCheck(ctx context.Context, wg *sync.WaitGroup) error {
+	if this.config.RuntimeCheckInterval == "" {
+		log.Println("runtime check disabled")
+		return nil
+	}
+	interval, err := time.ParseDuration(this.config.RuntimeCheckInterval)
+	if err != nil {
+		return err
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := this.CheckRuntimeStates()
+				if errors.Is(err, deploy.ErrNotSupported) {
+					log.Println("WARNING: runtime check stopped,", err)
+					return
+				}
+				if err != nil {
+					log.Println("ERROR: runtime check failed", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (this *Controller) CheckRuntimeStates() (err error) {
+	ctx, span := tracing.Start(context.Background(), "controller.CheckRuntimeStates")
+	defer tracing.End(span, &err)
+	var after *model.InstanceCursor
+	var batchSize int64 = 100
+	for {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		instances, err := this.db.AdminListInstances(timeoutCtx, batchSize, 0, "id", true, model.InstanceFilter{}, after)
+		if err != nil {
+			return err
+		}
+		if len(instances) == 0 {
+			return nil // done
+		}
+		cursor := model.NewInstanceCursor("id", true, instances[len(instances)-1])
+		after = &cursor
+
+		for _, instance := range instances {
+			status, err := this.deploymentClient.ContainerStatus(ctx, instance.ServiceId, instance.Restart)
+			if errors.Is(err, deploy.ErrNotSupported) {
+				return err
+			}
+			if err != nil {
+				log.Println("WARNING: unable to read runtime status of", instance.Id, err)
+				continue
+			}
+			if instance.Runtime != nil && reflect.DeepEqual(*instance.Runtime, status) {
+				continue
+			}
+			err = this.setRuntime(ctx, instance, status)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (this *Controller) setRuntime(ctx context.Context, instance model.Instance, status model.RuntimeStatus) error {
+	action, changed := runtimeEvent(instance.Runtime, status)
+	return this.transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.SetInstanceRuntime(timeoutCtx, instance.Id, status)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		log.Println(instance.Id, "runtime event", action, status.State, status.Message)
+		return this.publish(ctx, model.InstanceEvent{Action: action, Runtime: &status}, instance)
+	})
+}
+
+// runtimeEvent returns the event to publish for a change from previous to current.
+// Restarts of a running container are reported as crash, because the container exited before.
+// Nothing is reported for the first observation of restarts, because they may be arbitrarily old.
+func runtimeEvent(previous *model.RuntimeStatus, current model.RuntimeStatus) (action model.InstanceEventAction, ok bool) {
+	switch {
+	case current.State == model.RuntimeCrashed && (previous == nil || previous.State != model.RuntimeCrashed):
+		return model.InstanceCrashed, true
+	case current.State == model.RuntimeCompleted && (previous == nil || previous.State != model.RuntimeCompleted):
+		return model.InstanceCompleted, true
+	case previous != nil && current.Restarts > previous.Restarts:
+		return model.InstanceCrashed, true
+	default:
+		return "", false
+	}
+}
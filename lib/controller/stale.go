@@ -0,0 +1,152 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/notification"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+)
+
+// StartStaleCheck periodically marks instances as stale, if their kafka topic received no new messages within their expected interval.
+func (this *Controller) StartStaleCheck(ctx context.Context, wg *sync.WaitGroup) error {
+	if this.config.StaleCheckInterval == "" {
+		log.Println("stale check disabled")
+		return nil
+	}
+	interval, err := time.ParseDuration(this.config.StaleCheckInterval)
+	if err != nil {
+		return err
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := this.CheckStaleInstances()
+				if err != nil {
+					log.Println("ERROR: stale check failed", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (this *Controller) CheckStaleInstances() (err error) {
+	ctx, span := tracing.Start(context.Background(), "controller.CheckStaleInstances")
+	defer tracing.End(span, &err)
+	var after *model.InstanceCursor
+	var batchSize int64 = 100
+	for {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		instances, err := this.db.AdminListInstances(timeoutCtx, batchSize, 0, "id", true, model.InstanceFilter{}, after)
+		if err != nil {
+			return err
+		}
+		if len(instances) == 0 {
+			return nil // done
+		}
+		cursor := model.NewInstanceCursor("id", true, instances[len(instances)-1])
+		after = &cursor
+
+		watched := []model.Instance{}
+		topics := []string{}
+		for _, instance := range instances {
+			if instance.ExpectedInterval != "" {
+				watched = append(watched, instance)
+				topics = append(topics, instance.KafkaTopic)
+			}
+		}
+		if len(watched) == 0 {
+			continue
+		}
+		stats, err := this.kafkaAdmin.GetDataStats(ctx, topics)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, instance := range watched {
+			interval, err := time.ParseDuration(instance.ExpectedInterval)
+			if err != nil {
+				log.Println("WARNING: invalid expected interval of", instance.Id, err)
+				continue
+			}
+			lastActivity := instance.CreatedAt
+			instanceStats, ok := stats[instance.KafkaTopic]
+			if ok && instanceStats.LastMessageAt != nil && instanceStats.LastMessageAt.After(lastActivity) {
+				lastActivity = *instanceStats.LastMessageAt
+			}
+			stale := now.Sub(lastActivity) > interval
+			if stale == instance.Stale {
+				continue
+			}
+			err = this.setStale(instance, stale, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (this *Controller) setStale(instance model.Instance, stale bool, now time.Time) error {
+	var since *time.Time
+	if stale {
+		since = &now
+	}
+	action := model.InstanceRecovered
+	if stale {
+		action = model.InstanceStale
+	}
+	err := this.transaction(context.Background(), func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.SetInstanceStale(timeoutCtx, instance.Id, stale, since)
+		if err != nil {
+			return err
+		}
+		return this.publishEvent(ctx, action, instance, nil)
+	})
+	if err != nil {
+		return err
+	}
+	if !stale {
+		log.Println(instance.Id, "is producing data again")
+		return nil
+	}
+	log.Println(instance.Id, "is stale")
+	err = notification.Send(this.config.NotificationUrl, notification.Message{
+		UserId:  instance.Owner,
+		Title:   "Import stopped producing data",
+		Message: "The import '" + instance.Name + "' (" + instance.Id + ") produced no data within the expected interval of " + instance.ExpectedInterval + ".",
+		Topic:   notification.Topic,
+	})
+	if err != nil {
+		log.Println("WARNING: unable to send stale notification", instance.Id, err)
+	}
+	return nil
+}
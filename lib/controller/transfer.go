@@ -0,0 +1,173 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/keycloak"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// TransferInstance changes the owner of the instance. Requires the administrate right.
+func (this *Controller) TransferInstance(ctx context.Context, id string, newOwner string, jwt jwt.Token) (err error, code int) {
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return err, http.StatusServiceUnavailable
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.TransferInstance")
+	defer tracing.End(span, &err)
+	err, code = this.checkAdministrate(ctx, id, jwt)
+	if err != nil {
+		return err, code
+	}
+	err, code = this.checkNewOwner(ctx, newOwner)
+	if err != nil {
+		return err, code
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instance, exists, err := this.db.GetInstance(timeoutCtx, id, jwt)
+	if !exists {
+		return errors.New("not found"), http.StatusNotFound
+	}
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return this.transferInstance(ctx, instance, newOwner)
+}
+
+// AdminTransferInstances changes the owner of all instances of a user. Failed transfers do not stop the remaining transfers.
+func (this *Controller) AdminTransferInstances(ctx context.Context, userId string, newOwner string, jwt jwt.Token) (result model.TransferResult, err error, code int) {
+	ctx, done, err := this.startOperation(ctx)
+	if err != nil {
+		return result, err, http.StatusServiceUnavailable
+	}
+	defer done()
+	ctx, span := tracing.Start(ctx, "controller.AdminTransferInstances")
+	defer tracing.End(span, &err)
+	if !jwt.IsAdmin() {
+		return result, errors.New("only allowed for admins"), http.StatusForbidden
+	}
+	err, code = this.checkNewOwner(ctx, newOwner)
+	if err != nil {
+		return result, err, code
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	instances, err := this.db.AdminListInstances(timeoutCtx, -1, 0, "id", true, model.InstanceFilter{Owner: userId}, nil)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	result = model.TransferResult{Transferred: []string{}, Errors: map[string]string{}}
+	for _, instance := range instances {
+		err, _ := this.transferInstance(ctx, instance, newOwner)
+		if err != nil {
+			result.Errors[instance.Id] = err.Error()
+		} else {
+			result.Transferred = append(result.Transferred, instance.Id)
+		}
+	}
+	return result, nil, http.StatusOK
+}
+
+// checkNewOwner rejects empty and unknown users. Users are looked up by a token exchange at keycloak.
+func (this *Controller) checkNewOwner(ctx context.Context, newOwner string) (err error, code int) {
+	if newOwner == "" {
+		return errors.New("missing new_owner"), http.StatusBadRequest
+	}
+	if !this.userTokens.Enabled() {
+		return errors.New("transfer is not configured"), http.StatusNotImplemented
+	}
+	_, err = this.userTokens.Get(ctx, newOwner)
+	if errors.Is(err, keycloak.ErrUnknownUser) {
+		return errors.New("unknown new_owner"), http.StatusBadRequest
+	}
+	if err != nil {
+		return err, http.StatusBadGateway
+	}
+	return nil, http.StatusOK
+}
+
+// transferInstance updates the user label of the deployment, without redeploying it, and moves the permissions
+func (this *Controller) transferInstance(ctx context.Context, instance model.Instance, newOwner string) (err error, code int) {
+	defer this.metrics.ObserveOperation("transfer", time.Now(), &err)
+	if instance.Owner == newOwner {
+		return nil, http.StatusOK
+	}
+	// claiming the next version first lets concurrent updates fail before the container is touched
+	claimedVersion := instance.Version + 1
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err = this.db.SetInstanceVersion(timeoutCtx, instance.Id, instance.Version, claimedVersion)
+	if errors.Is(err, model.ErrVersionConflict) {
+		return err, http.StatusPreconditionFailed
+	}
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	// the permissions are moved within the transaction and restored, if it fails
+	permissions, err, _ := this.permv2.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id)
+	if err != nil {
+		this.releaseVersion(ctx, instance.Id, claimedVersion, instance.Version)
+		return err, http.StatusInternalServerError
+	}
+	err = this.deploymentClient.SetContainerOwner(ctx, instance.ServiceId, instance.Restart, newOwner)
+	if err != nil {
+		this.releaseVersion(ctx, instance.Id, claimedVersion, instance.Version)
+		return err, http.StatusInternalServerError
+	}
+	err = this.transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err := this.db.TransferInstance(timeoutCtx, instance, newOwner, claimedVersion)
+		if err != nil {
+			return err
+		}
+		transferred := instance
+		transferred.Owner = newOwner
+		transferred.Version = claimedVersion + 1
+		return this.publish(ctx, model.InstanceEvent{Action: model.InstanceTransferred, PreviousOwner: instance.Owner}, transferred)
+	})
+	if err != nil {
+		this.undoTransfer(ctx, instance, permissions.ResourcePermissions)
+		this.releaseVersion(ctx, instance.Id, claimedVersion, instance.Version)
+	}
+	if errors.Is(err, model.ErrVersionConflict) {
+		return err, http.StatusPreconditionFailed
+	}
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// undoTransfer restores the owner of the container and the permissions of an instance, whose transfer could not be stored
+func (this *Controller) undoTransfer(ctx context.Context, instance model.Instance, permissions permV2Client.ResourcePermissions) {
+	err := this.deploymentClient.SetContainerOwner(ctx, instance.ServiceId, instance.Restart, instance.Owner)
+	if err != nil {
+		log.Println("ERROR: unable to restore container owner of failed transfer", instance.Id, err)
+	}
+	_, err, _ = this.permv2.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permissions)
+	if err != nil {
+		log.Println("ERROR: unable to restore permissions of failed transfer", instance.Id, err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+func TestTransferIsUndoneWhenPublishFails(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.create(t, "user1", model.Instance{Name: "test"})
+	env.events.fail(true)
+
+	err, _ := env.controller.transferInstance(context.Background(), created, "user2")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if owner := env.deploy.owner(created.ServiceId); owner != "user1" {
+		t.Error("container owner not restored", owner)
+	}
+	stored, err, _ := env.controller.ReadInstance(context.Background(), created.Id, testToken("user1"))
+	if err != nil {
+		t.Fatal("permissions not restored", err)
+	}
+	if stored.Owner != "user1" || stored.Version != created.Version {
+		t.Error("instance not restored", stored.Owner, stored.Version)
+	}
+	_, err, _ = env.controller.ReadInstance(context.Background(), created.Id, testToken("user2"))
+	if err == nil {
+		t.Error("new owner kept permissions of failed transfer")
+	}
+
+	// the claimed version was released, so the transfer can be retried
+	env.events.fail(false)
+	err, _ = env.controller.transferInstance(context.Background(), created, "user2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := env.deploy.owner(created.ServiceId); owner != "user2" {
+		t.Error(owner)
+	}
+	stored, err, _ = env.controller.ReadInstance(context.Background(), created.Id, testToken("user2"))
+	if err != nil || stored.Owner != "user2" {
+		t.Error(stored.Owner, err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/SENERGY-Platform/import-deploy/lib/webhooks"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/hashicorp/go-uuid"
+)
+
+func (this *Controller) ListWebhooks(ctx context.Context, jwt jwt.Token, limit int64, offset int64) (results []model.Webhook, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.ListWebhooks")
+	defer tracing.End(span, &err)
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	results, err = this.db.ListWebhooks(timeoutCtx, jwt.GetUserId(), limit, offset)
+	if err != nil {
+		return results, err, http.StatusInternalServerError
+	}
+	for idx := range results {
+		results[idx].Secret = ""
+	}
+	return results, nil, http.StatusOK
+}
+
+func (this *Controller) ReadWebhook(ctx context.Context, id string, jwt jwt.Token) (result model.Webhook, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.ReadWebhook")
+	defer tracing.End(span, &err)
+	result, err, errCode = this.getOwnWebhook(ctx, id, jwt)
+	if err != nil {
+		return result, err, errCode
+	}
+	result.Secret = ""
+	return result, nil, http.StatusOK
+}
+
+// CreateWebhook returns the created webhook including its secret. The secret is generated if not provided.
+func (this *Controller) CreateWebhook(ctx context.Context, webhook model.Webhook, jwt jwt.Token) (result model.Webhook, err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.CreateWebhook")
+	defer tracing.End(span, &err)
+	if webhook.Id != "" {
+		return result, errors.New("explicit setting of id not allowed"), http.StatusBadRequest
+	}
+	err, code = this.validateWebhook(ctx, webhook, jwt)
+	if err != nil {
+		return result, err, code
+	}
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	webhook.Id = id
+	if webhook.Secret == "" {
+		webhook.Secret, err = generateSecret()
+		if err != nil {
+			return result, err, http.StatusInternalServerError
+		}
+	}
+	webhook.Owner = jwt.GetUserId()
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err = this.db.SetWebhook(timeoutCtx, webhook)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	return webhook, nil, http.StatusOK
+}
+
+// SetWebhook keeps the existing secret if none is provided
+func (this *Controller) SetWebhook(ctx context.Context, webhook model.Webhook, jwt jwt.Token) (err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.SetWebhook")
+	defer tracing.End(span, &err)
+	existing, err, code := this.getOwnWebhook(ctx, webhook.Id, jwt)
+	if err != nil {
+		return err, code
+	}
+	err, code = this.validateWebhook(ctx, webhook, jwt)
+	if err != nil {
+		return err, code
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+	webhook.Owner = existing.Owner
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.UpdatedAt = time.Now()
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err = this.db.SetWebhook(timeoutCtx, webhook)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+func (this *Controller) DeleteWebhook(ctx context.Context, id string, jwt jwt.Token) (err error, code int) {
+	ctx, span := tracing.Start(ctx, "controller.DeleteWebhook")
+	defer tracing.End(span, &err)
+	_, err, code = this.getOwnWebhook(ctx, id, jwt)
+	if err != nil {
+		return err, code
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err = this.db.RemoveWebhook(timeoutCtx, id)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusNoContent
+}
+
+func (this *Controller) ListWebhookDeliveries(ctx context.Context, id string, jwt jwt.Token, limit int64, offset int64) (results []model.WebhookDelivery, err error, errCode int) {
+	ctx, span := tracing.Start(ctx, "controller.ListWebhookDeliveries")
+	defer tracing.End(span, &err)
+	_, err, errCode = this.getOwnWebhook(ctx, id, jwt)
+	if err != nil {
+		return results, err, errCode
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	results, err = this.db.ListWebhookDeliveries(timeoutCtx, id, limit, offset)
+	if err != nil {
+		return results, err, http.StatusInternalServerError
+	}
+	return results, nil, http.StatusOK
+}
+
+func (this *Controller) getOwnWebhook(ctx context.Context, id string, jwt jwt.Token) (result model.Webhook, err error, code int) {
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	result, exists, err := this.db.GetWebhook(timeoutCtx, id)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	if !exists || result.Owner != jwt.GetUserId() {
+		return result, errors.New("not found"), http.StatusNotFound
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Controller) validateWebhook(ctx context.Context, webhook model.Webhook, jwt jwt.Token) (err error, code int) {
+	u, err := url.Parse(webhook.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https url"), http.StatusBadRequest
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err = webhooks.CheckTarget(timeoutCtx, this.config.WebhookAllowedNetworks, u.Hostname())
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	for _, action := range webhook.Actions {
+		switch action {
+		case model.InstanceCreated, model.InstanceUpdated, model.InstanceDeleted, model.InstanceTransferred, model.InstanceStale, model.InstanceRecovered, model.InstanceCrashed, model.InstanceCompleted:
+		default:
+			return errors.New("unknown action " + string(action)), http.StatusBadRequest
+		}
+	}
+	if len(webhook.InstanceIds) == 0 {
+		return nil, http.StatusOK
+	}
+	_, span := tracing.Start(ctx, "permissions.CheckMultiplePermissions")
+	access, err, _ := this.permv2.CheckMultiplePermissions(jwt.Token, model.PermV2InstanceTopic, webhook.InstanceIds, permV2Client.Read)
+	tracing.End(span, &err)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	for _, id := range webhook.InstanceIds {
+		if !access[id] {
+			return errors.New("no read access to instance " + id), http.StatusForbidden
+		}
+	}
+	return nil, http.StatusOK
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
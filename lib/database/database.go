@@ -21,7 +21,6 @@ import (
 	model2 "github.com/SENERGY-Platform/permissions-v2/pkg/model"
 	"log"
 	"slices"
-	"sync"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
 	"github.com/SENERGY-Platform/import-deploy/lib/database/mongo"
@@ -30,8 +29,8 @@ import (
 	"golang.org/x/exp/maps"
 )
 
-func New(conf config.Config, perm permV2Client.Client, ctx context.Context, wg *sync.WaitGroup) (db Database, err error) {
-	mong, err := mongo.New(perm, conf, ctx, wg)
+func New(conf config.Config, perm permV2Client.Client, ctx context.Context) (db Database, err error) {
+	mong, err := mongo.New(perm, conf, ctx)
 	if err != nil {
 		return db, err
 	}
@@ -42,6 +41,7 @@ func New(conf config.Config, perm permV2Client.Client, ctx context.Context, wg *
 	}
 	err = migrate(conf, mong, perm, ctx)
 	if err != nil {
+		mong.Disconnect()
 		return db, err
 	}
 	return
@@ -72,7 +72,7 @@ func migrate(config config.Config, db *mongo.Mongo, perm permV2Client.Client, ct
 
 	log.Println("migrating instance permissions")
 
-	instances, err := db.AdminListInstances(ctx, -1, 0, "", true, "", true)
+	instances, err := db.AdminListInstances(ctx, -1, 0, "", true, model.InstanceFilter{}, nil)
 	if err != nil {
 		return err
 	}
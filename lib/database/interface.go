@@ -18,18 +18,49 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 )
 
 type Database interface {
 	Disconnect()
+	Ping(ctx context.Context) error
+	Transaction(ctx context.Context) (resultCtx context.Context, close func(success bool) error, err error)
 
-	ListInstances(ctx context.Context, limit int64, offset int64, sort string, jwt jwt.Token, asc bool, search string, includeGenerated bool) (result []model.Instance, err error)
+	ListInstances(ctx context.Context, limit int64, offset int64, sort string, jwt jwt.Token, asc bool, filter model.InstanceFilter, after *model.InstanceCursor) (result []model.Instance, err error)
 	GetInstance(ctx context.Context, id string, jwt jwt.Token) (instance model.Instance, exists bool, err error)
 	CreateInstance(ctx context.Context, instance model.Instance, jwt jwt.Token) error
-	SetInstance(ctx context.Context, instance model.Instance, jwt jwt.Token) error
+	SetInstance(ctx context.Context, instance model.Instance, jwt jwt.Token, previousVersion int64) error
+	SetInstanceServiceId(ctx context.Context, id string, serviceId string) error
+	SetInstanceVersion(ctx context.Context, id string, previousVersion int64, version int64) error
+	SetInstanceStale(ctx context.Context, id string, stale bool, since *time.Time) error
+	SetInstanceRuntime(ctx context.Context, id string, runtime model.RuntimeStatus) error
+	TransferInstance(ctx context.Context, instance model.Instance, newOwner string, previousVersion int64) error
+	SetInstancePermissions(ctx context.Context, id string, permissions permV2Client.ResourcePermissions) (result permV2Client.ResourcePermissions, err error, code int)
 	RemoveInstance(ctx context.Context, id string, jwt jwt.Token) error
-	CountInstances(ctx context.Context, jwt jwt.Token, search string, includeGenerated bool) (count int64, err error)
+	CountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (count int64, err error)
+	AdminListInstances(ctx context.Context, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor) (result []model.Instance, err error)
+	AdminCountInstances(ctx context.Context, filter model.InstanceFilter) (count int64, err error)
+	CountInstancesByImportTypeAndState(ctx context.Context) (result []model.InstanceCount, err error)
+
+	ReserveIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (existing model.IdempotencyKey, reserved bool, err error)
+	CompleteIdempotencyKey(ctx context.Context, key model.IdempotencyKey, instanceId string, response string) error
+	RemoveIdempotencyKey(ctx context.Context, key model.IdempotencyKey) error
+
+	AddInstanceEvent(ctx context.Context, event model.InstanceEvent) error
+	ListInstanceEvents(ctx context.Context, limit int64) (result []model.InstanceEvent, err error)
+	RemoveInstanceEvent(ctx context.Context, id string) error
+
+	ListWebhooks(ctx context.Context, owner string, limit int64, offset int64) (result []model.Webhook, err error)
+	ListWebhooksForEvent(ctx context.Context, action model.InstanceEventAction, instanceId string) (result []model.Webhook, err error)
+	GetWebhook(ctx context.Context, id string) (webhook model.Webhook, exists bool, err error)
+	SetWebhook(ctx context.Context, webhook model.Webhook) error
+	RemoveWebhook(ctx context.Context, id string) error
+	SetWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, webhookId string, limit int64, offset int64) (result []model.WebhookDelivery, err error)
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) (result []model.WebhookDelivery, err error)
+	RemoveWebhookDelivery(ctx context.Context, id string) error
 }
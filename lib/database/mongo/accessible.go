@@ -0,0 +1,148 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mongo
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// readableBatchSize is the number of instances checked or ids listed with one permissions-v2 request
+const readableBatchSize int64 = 500
+
+// readableIdLimit is the maximum number of readable ids passed to one query as $in filter
+const readableIdLimit int64 = 10000
+
+// listReadable lists the instances the token may read. find queries the instances with the given ids, or all instances
+// for nil ids, and applies sort, limit and skip.
+// The readable ids are paged from permissions-v2; up to readableIdLimit ids are filtered by one query, which applies
+// limit and offset. Tokens with more readable ids, and admins, can read a large part of the instances: for them the
+// instances are queried in batches, which are checked with CheckMultiplePermissions until the page is filled.
+// A limit <= 0 lists all readable instances.
+func listReadable(ctx context.Context, perm permV2Client.Client, token jwt.Token, limit int64, offset int64, find func(ids []string, limit int64, skip int64) ([]model.Instance, error)) (result []model.Instance, err error) {
+	scan := func(limit int64, skip int64) ([]model.Instance, error) {
+		return find(nil, limit, skip)
+	}
+	if token.IsAdmin() {
+		return scanReadable(ctx, perm, token, limit, offset, scan)
+	}
+	ids, complete, err := readableIds(ctx, perm, token, readableIdLimit)
+	if err != nil {
+		return nil, err
+	}
+	if complete {
+		if len(ids) == 0 {
+			return []model.Instance{}, nil
+		}
+		return find(ids, limit, offset)
+	}
+	return scanReadable(ctx, perm, token, limit, offset, scan)
+}
+
+// readableIds pages through the ids the token may read. complete is false, if the token may read more than maxIds instances.
+func readableIds(ctx context.Context, perm permV2Client.Client, token jwt.Token, maxIds int64) (ids []string, complete bool, err error) {
+	ids = []string{}
+	var offset int64
+	for {
+		_, span := tracing.Start(ctx, "permissions.ListAccessibleResourceIds")
+		page, err, _ := perm.ListAccessibleResourceIds(token.Token, model.PermV2InstanceTopic, permV2Client.ListOptions{Limit: readableBatchSize, Offset: offset}, permV2Client.Read)
+		tracing.End(span, &err)
+		if err != nil {
+			return nil, false, err
+		}
+		ids = append(ids, page...)
+		if int64(len(page)) < readableBatchSize {
+			return ids, true, nil
+		}
+		if int64(len(ids)) >= maxIds {
+			return nil, false, nil
+		}
+		offset += readableBatchSize
+	}
+}
+
+// scanReadable pages through the instances returned by find and keeps those the token may read,
+// until limit readable instances after offset are collected. A limit <= 0 collects all readable instances.
+func scanReadable(ctx context.Context, perm permV2Client.Client, token jwt.Token, limit int64, offset int64, find func(limit int64, skip int64) ([]model.Instance, error)) (result []model.Instance, err error) {
+	result = []model.Instance{}
+	batchSize := max(readableBatchSize, limit)
+	var skip int64
+	for {
+		batch, err := find(batchSize, skip)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+		ids := make([]string, 0, len(batch))
+		for _, instance := range batch {
+			ids = append(ids, instance.Id)
+		}
+		_, span := tracing.Start(ctx, "permissions.CheckMultiplePermissions")
+		access, err, _ := perm.CheckMultiplePermissions(token.Token, model.PermV2InstanceTopic, ids, permV2Client.Read)
+		tracing.End(span, &err)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range batch {
+			if !access[instance.Id] {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			result = append(result, instance)
+			if limit > 0 && int64(len(result)) >= limit {
+				return result, nil
+			}
+		}
+		if int64(len(batch)) < batchSize {
+			return result, nil
+		}
+		skip += batchSize
+	}
+}
+
+// countReadable pages through the ids the token may read and sums the matches of each page returned by count
+func countReadable(ctx context.Context, perm permV2Client.Client, token jwt.Token, count func(ids []string) (int64, error)) (result int64, err error) {
+	var offset int64
+	for {
+		_, span := tracing.Start(ctx, "permissions.ListAccessibleResourceIds")
+		ids, err, _ := perm.ListAccessibleResourceIds(token.Token, model.PermV2InstanceTopic, permV2Client.ListOptions{Limit: readableBatchSize, Offset: offset}, permV2Client.Read)
+		tracing.End(span, &err)
+		if err != nil {
+			return 0, err
+		}
+		if len(ids) == 0 {
+			return result, nil
+		}
+		matches, err := count(ids)
+		if err != nil {
+			return 0, err
+		}
+		result += matches
+		if int64(len(ids)) < readableBatchSize {
+			return result, nil
+		}
+		offset += readableBatchSize
+	}
+}
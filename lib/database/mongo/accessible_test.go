@@ -0,0 +1,342 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package mongo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+// fakePermissions returns an in-memory permissions-v2 with count instances; every shareEvery-th instance is readable by user1
+func fakePermissions(tb testing.TB, count int, shareEvery int) (perm permV2Client.Client, instances []model.Instance) {
+	perm, err := permV2Client.NewTestClient(tb.Context())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_, err, _ = perm.SetTopic(permV2Client.InternalAdminToken, permV2Client.Topic{
+		Id: model.PermV2InstanceTopic,
+		DefaultPermissions: permV2Client.ResourcePermissions{
+			RolePermissions: map[string]permV2Client.PermissionsMap{"admin": {Read: true, Write: true, Execute: true, Administrate: true}},
+		},
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	for i := 0; i < count; i++ {
+		instance := model.Instance{Id: fmt.Sprintf("urn:infai:ses:import:%06d", i), Owner: "owner"}
+		permissions := permV2Client.ResourcePermissions{UserPermissions: map[string]permV2Client.PermissionsMap{}}
+		model.SetDefaultPermissions(instance, permissions)
+		if i%shareEvery == 0 {
+			permissions.UserPermissions["user1"] = permV2Client.PermissionsMap{Read: true}
+		}
+		_, err, _ = perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permissions)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		instances = append(instances, instance)
+	}
+	return perm, instances
+}
+
+// fakeFind filters instances by ids, if not nil, and pages through them like a mongo query with limit and skip
+func fakeFind(instances []model.Instance) func(ids []string, limit int64, skip int64) ([]model.Instance, error) {
+	return func(ids []string, limit int64, skip int64) ([]model.Instance, error) {
+		filtered := instances
+		if ids != nil {
+			set := map[string]bool{}
+			for _, id := range ids {
+				set[id] = true
+			}
+			filtered = []model.Instance{}
+			for _, instance := range instances {
+				if set[instance.Id] {
+					filtered = append(filtered, instance)
+				}
+			}
+		}
+		start := min(skip, int64(len(filtered)))
+		end := int64(len(filtered))
+		if limit > 0 {
+			end = min(start+limit, end)
+		}
+		return filtered[start:end], nil
+	}
+}
+
+// fakeScan pages through all instances, like find without id filter
+func fakeScan(instances []model.Instance) func(limit int64, skip int64) ([]model.Instance, error) {
+	find := fakeFind(instances)
+	return func(limit int64, skip int64) ([]model.Instance, error) {
+		return find(nil, limit, skip)
+	}
+}
+
+// previousListReadable lists like before paging: all readable ids are requested at once and passed to one query.
+// Admins skipped the id filter.
+func previousListReadable(perm permV2Client.Client, token jwt.Token, limit int64, offset int64, find func(ids []string, limit int64, skip int64) ([]model.Instance, error)) ([]model.Instance, error) {
+	if token.IsAdmin() {
+		return find(nil, limit, offset)
+	}
+	ids, err, _ := perm.ListAccessibleResourceIds(token.Token, model.PermV2InstanceTopic, permV2Client.ListOptions{}, permV2Client.Read)
+	if err != nil {
+		return nil, err
+	}
+	return find(ids, limit, offset)
+}
+
+// fakeCount counts the given ids, like a mongo count with an id filter and without further filters
+func fakeCount(ids []string) (int64, error) {
+	return int64(len(ids)), nil
+}
+
+func instanceIds(instances []model.Instance) (ids []string) {
+	for _, instance := range instances {
+		ids = append(ids, instance.Id)
+	}
+	return ids
+}
+
+func testToken(tb testing.TB, userId string, roles ...string) jwt.Token {
+	encode := func(value any) string {
+		b, _ := json.Marshal(value)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	raw := encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." +
+		encode(map[string]any{"sub": userId, "realm_access": map[string][]string{"roles": append(roles, "user")}}) + ".c2lnbmF0dXJl"
+	token, err := jwt.Parse(raw)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return token
+}
+
+func TestListReadable(t *testing.T) {
+	perm, instances := fakePermissions(t, 1200, 10)
+	ctx := context.Background()
+	user := testToken(t, "user1")
+
+	all, err := listReadable(ctx, perm, user, -1, 0, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 120 {
+		t.Fatal(len(all))
+	}
+	page, err := listReadable(ctx, perm, user, 10, 55, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(instanceIds(page), instanceIds(all[55:65])) {
+		t.Error(page)
+	}
+	last, err := listReadable(ctx, perm, user, 10, 115, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(instanceIds(last), instanceIds(all[115:])) {
+		t.Error(last)
+	}
+
+	admin, err := listReadable(ctx, perm, testToken(t, "admin1", "admin"), -1, 0, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(admin) != len(instances) {
+		t.Error(len(admin))
+	}
+	other, err := listReadable(ctx, perm, testToken(t, "user2"), -1, 0, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 0 {
+		t.Error(len(other))
+	}
+}
+
+func TestScanReadable(t *testing.T) {
+	perm, instances := fakePermissions(t, 1200, 10)
+	ctx := context.Background()
+	user := testToken(t, "user1")
+
+	expected, err := listReadable(ctx, perm, user, -1, 0, fakeFind(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := scanReadable(ctx, perm, user, -1, 0, fakeScan(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(instanceIds(all), instanceIds(expected)) {
+		t.Error(len(all), len(expected))
+	}
+	page, err := scanReadable(ctx, perm, user, 10, 55, fakeScan(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(instanceIds(page), instanceIds(expected[55:65])) {
+		t.Error(page)
+	}
+	admin, err := scanReadable(ctx, perm, testToken(t, "admin1", "admin"), 20, 1190, fakeScan(instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(instanceIds(admin), instanceIds(instances[1190:])) {
+		t.Error(admin)
+	}
+}
+
+func TestReadableIds(t *testing.T) {
+	perm, _ := fakePermissions(t, 1200, 10)
+	ctx := context.Background()
+	ids, complete, err := readableIds(ctx, perm, testToken(t, "user1"), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !complete || len(ids) != 120 {
+		t.Error(complete, len(ids))
+	}
+	_, complete, err = readableIds(ctx, perm, testToken(t, "admin1", "admin"), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if complete {
+		t.Error("expected incomplete ids for more readable instances than the limit")
+	}
+	ids, complete, err = readableIds(ctx, perm, testToken(t, "admin1", "admin"), 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !complete || len(ids) != 1200 {
+		t.Error(complete, len(ids))
+	}
+}
+
+func TestCountReadable(t *testing.T) {
+	perm, _ := fakePermissions(t, 1200, 1)
+	ctx := context.Background()
+	count, err := countReadable(ctx, perm, testToken(t, "user1"), fakeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1200 {
+		t.Error(count)
+	}
+	count, err = countReadable(ctx, perm, testToken(t, "user2"), fakeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Error(count)
+	}
+}
+
+// BenchmarkListReadable compares the listing before paging ("previous"), the batch scan ("scan")
+// and listReadable for many instances of which the user may read few ("sparse") or many ("dense")
+func BenchmarkListReadable(b *testing.B) {
+	ctx := context.Background()
+	user := testToken(b, "user1")
+	admin := testToken(b, "admin1", "admin")
+	for _, scenario := range []struct {
+		name       string
+		count      int
+		shareEvery int
+	}{
+		{name: "sparse", count: 20000, shareEvery: 1000},
+		{name: "dense", count: 20000, shareEvery: 10},
+	} {
+		perm, instances := fakePermissions(b, scenario.count, scenario.shareEvery)
+		readable := int64(scenario.count / scenario.shareEvery)
+		for _, page := range []struct {
+			name   string
+			offset int64
+		}{
+			{name: "first page", offset: 0},
+			{name: "last page", offset: readable - 10},
+		} {
+			b.Run(scenario.name+"/"+page.name+"/previous", func(b *testing.B) {
+				for b.Loop() {
+					_, err := previousListReadable(perm, user, 20, page.offset, fakeFind(instances))
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run(scenario.name+"/"+page.name+"/scan", func(b *testing.B) {
+				for b.Loop() {
+					_, err := scanReadable(ctx, perm, user, 20, page.offset, fakeScan(instances))
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run(scenario.name+"/"+page.name+"/paged ids", func(b *testing.B) {
+				for b.Loop() {
+					_, err := listReadable(ctx, perm, user, 20, page.offset, fakeFind(instances))
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+		b.Run(scenario.name+"/admin/previous", func(b *testing.B) {
+			for b.Loop() {
+				_, err := previousListReadable(perm, admin, 20, 0, fakeFind(instances))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(scenario.name+"/admin/paged ids", func(b *testing.B) {
+			for b.Loop() {
+				_, err := listReadable(ctx, perm, admin, 20, 0, fakeFind(instances))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCountReadable(b *testing.B) {
+	perm, _ := fakePermissions(b, 5000, 10)
+	ctx := context.Background()
+	user := testToken(b, "user1")
+	admin := testToken(b, "admin1", "admin")
+	b.Run("user", func(b *testing.B) {
+		for b.Loop() {
+			_, err := countReadable(ctx, perm, user, fakeCount)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("admin", func(b *testing.B) {
+		for b.Loop() {
+			_, err := countReadable(ctx, perm, admin, fakeCount)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
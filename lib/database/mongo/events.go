@@ -0,0 +1,80 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"log"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var eventIdKey string
+var eventTimeKey string
+
+func init() {
+	var err error
+	eventIdKey, err = getBsonFieldName(model.InstanceEvent{}, "Id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventTimeKey, err = getBsonFieldName(model.InstanceEvent{}, "Time")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		collection := db.eventOutboxCollection()
+		err = db.ensureIndex(collection, "eventIdindex", eventIdKey, true, true)
+		if err != nil {
+			return err
+		}
+		err = db.ensureIndex(collection, "eventTimeindex", eventTimeKey, true, false)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (this *Mongo) eventOutboxCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoEventOutboxCollection)
+}
+
+func (this *Mongo) AddInstanceEvent(ctx context.Context, event model.InstanceEvent) error {
+	_, err := this.eventOutboxCollection().InsertOne(ctx, event)
+	return err
+}
+
+// ListInstanceEvents returns the oldest events of the outbox
+func (this *Mongo) ListInstanceEvents(ctx context.Context, limit int64) (result []model.InstanceEvent, err error) {
+	opt := options.Find().SetLimit(limit).SetSort(bson.D{{Key: eventTimeKey, Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := this.eventOutboxCollection().Find(ctx, bson.M{}, opt)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(ctx, &result)
+	return result, err
+}
+
+func (this *Mongo) RemoveInstanceEvent(ctx context.Context, id string) error {
+	_, err := this.eventOutboxCollection().DeleteOne(ctx, bson.M{eventIdKey: id})
+	return err
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var idempotencyIdKey string
+var idempotencyInstanceIdKey string
+var idempotencyResponseKey string
+var idempotencyCreatedAtKey string
+var idempotencyExpiresAtKey string
+
+func init() {
+	var err error
+	idempotencyIdKey, err = getBsonFieldName(model.IdempotencyKey{}, "Id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	idempotencyInstanceIdKey, err = getBsonFieldName(model.IdempotencyKey{}, "InstanceId")
+	if err != nil {
+		log.Fatal(err)
+	}
+	idempotencyResponseKey, err = getBsonFieldName(model.IdempotencyKey{}, "Response")
+	if err != nil {
+		log.Fatal(err)
+	}
+	idempotencyCreatedAtKey, err = getBsonFieldName(model.IdempotencyKey{}, "CreatedAt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	idempotencyExpiresAtKey, err = getBsonFieldName(model.IdempotencyKey{}, "ExpiresAt")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		collection := db.idempotencyCollection()
+		err = db.ensureIndex(collection, "idempotencyIdindex", idempotencyIdKey, true, true)
+		if err != nil {
+			return err
+		}
+		ctx, _ := getTimeoutContext()
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: idempotencyExpiresAtKey, Value: 1}},
+			Options: options.Index().SetName("idempotencyExpiresAtindex").SetExpireAfterSeconds(0),
+		})
+		return err
+	})
+}
+
+func (this *Mongo) idempotencyCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoIdempotencyCollection)
+}
+
+// ReserveIdempotencyKey stores the key, if no key with the same id exists. Otherwise, the existing key is returned.
+// Expired keys are removed by mongo with a delay of up to a minute; the caller has to check ExpiresAt.
+func (this *Mongo) ReserveIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (existing model.IdempotencyKey, reserved bool, err error) {
+	_, err = this.idempotencyCollection().InsertOne(ctx, key)
+	if err == nil {
+		return key, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return existing, false, err
+	}
+	err = this.idempotencyCollection().FindOne(ctx, bson.M{idempotencyIdKey: key.Id}).Decode(&existing)
+	return existing, false, err
+}
+
+// CompleteIdempotencyKey stores the response of the key, if it was not replaced by a newer reservation
+func (this *Mongo) CompleteIdempotencyKey(ctx context.Context, key model.IdempotencyKey, instanceId string, response string) error {
+	result, err := this.idempotencyCollection().UpdateOne(ctx, bson.M{idempotencyIdKey: key.Id, idempotencyCreatedAtKey: key.CreatedAt}, bson.M{"$set": bson.M{
+		idempotencyInstanceIdKey: instanceId,
+		idempotencyResponseKey:   response,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("idempotency key was replaced by a newer reservation")
+	}
+	return nil
+}
+
+// RemoveIdempotencyKey removes the key, if it was not replaced by a newer reservation
+func (this *Mongo) RemoveIdempotencyKey(ctx context.Context, key model.IdempotencyKey) error {
+	_, err := this.idempotencyCollection().DeleteOne(ctx, bson.M{idempotencyIdKey: key.Id, idempotencyCreatedAtKey: key.CreatedAt})
+	return err
+}
@@ -23,8 +23,12 @@ import (
 	model2 "github.com/SENERGY-Platform/permissions-v2/pkg/model"
 	"log"
 	"net/http"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
 	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"go.mongodb.org/mongo-driver/bson"
@@ -40,6 +44,14 @@ const createdAtFieldName = "CreatedAt"
 const updatedAtFieldName = "UpdatedAt"
 const generatedFieldName = "Generated"
 const imageFieldName = "Image"
+const importTypeIdFieldName = "ImportTypeId"
+const staleFieldName = "Stale"
+const staleSinceFieldName = "StaleSince"
+const restartFieldName = "Restart"
+const serviceIdFieldName = "ServiceId"
+const labelsFieldName = "Labels"
+const versionFieldName = "Version"
+const runtimeFieldName = "Runtime"
 
 var idKey string
 var nameKey string
@@ -48,6 +60,14 @@ var createdAtKey string
 var updatedAtKey string
 var generatedKey string
 var imageKey string
+var importTypeIdKey string
+var staleKey string
+var staleSinceKey string
+var restartKey string
+var serviceIdKey string
+var labelsKey string
+var versionKey string
+var runtimeKey string
 
 func init() {
 	var err error
@@ -79,6 +99,38 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	importTypeIdKey, err = getBsonFieldName(model.Instance{}, importTypeIdFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	staleKey, err = getBsonFieldName(model.Instance{}, staleFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	staleSinceKey, err = getBsonFieldName(model.Instance{}, staleSinceFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	restartKey, err = getBsonFieldName(model.Instance{}, restartFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	serviceIdKey, err = getBsonFieldName(model.Instance{}, serviceIdFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	labelsKey, err = getBsonFieldName(model.Instance{}, labelsFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	versionKey, err = getBsonFieldName(model.Instance{}, versionFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runtimeKey, err = getBsonFieldName(model.Instance{}, runtimeFieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	CreateCollections = append(CreateCollections, func(db *Mongo) error {
 		collection := db.client.Database(db.config.MongoTable).Collection(db.config.MongoImportTypeCollection)
@@ -90,6 +142,18 @@ func init() {
 		if err != nil {
 			return err
 		}
+		for name, key := range map[string]string{
+			"instanceImportTypeIdindex": importTypeIdKey,
+			"instanceImageindex":        imageKey,
+			"instanceCreatedAtindex":    createdAtKey,
+			"instanceUpdatedAtindex":    updatedAtKey,
+			"instanceLabelsindex":       labelsKey + ".$**",
+		} {
+			err = db.ensureIndex(collection, name, key, true, false)
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
@@ -99,7 +163,9 @@ func (this *Mongo) instanceCollection() *mongo.Collection {
 }
 
 func (this *Mongo) GetInstance(ctx context.Context, id string, jwt jwt.Token) (instance model.Instance, exists bool, err error) {
+	_, span := tracing.Start(ctx, "permissions.CheckPermission")
 	ok, err, _ := this.perm.CheckPermission(jwt.Token, model.PermV2InstanceTopic, id, permV2Client.Read)
+	tracing.End(span, &err)
 	if err != nil {
 		return instance, false, err
 	}
@@ -128,61 +194,94 @@ func (this *Mongo) GetInstance(ctx context.Context, id string, jwt jwt.Token) (i
 	return instance, true, err
 }
 
-func (this *Mongo) AdminListInstances(ctx context.Context, limit int64, offset int64, sort string, asc bool, search string, includeGenerated bool) (result []model.Instance, err error) {
-	return this.listInstances(ctx, limit, offset, sort, asc, search, includeGenerated, []string{}, true)
+// AdminListInstances lists the instances of all users
+func (this *Mongo) AdminListInstances(ctx context.Context, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor) (result []model.Instance, err error) {
+	return this.listInstances(ctx, limit, offset, sort, asc, filter, after, []string{}, true)
 }
 
-func (this *Mongo) ListInstances(ctx context.Context, limit int64, offset int64, sort string, jwt jwt.Token, asc bool, search string, includeGenerated bool) (result []model.Instance, err error) {
-	ids, err, _ := this.perm.ListAccessibleResourceIds(jwt.Token, model.PermV2InstanceTopic, permV2Client.ListOptions{}, permV2Client.Read)
-	if err != nil {
-		return nil, err
+func (this *Mongo) ListInstances(ctx context.Context, limit int64, offset int64, sort string, jwt jwt.Token, asc bool, filter model.InstanceFilter, after *model.InstanceCursor) (result []model.Instance, err error) {
+	return listReadable(ctx, this.perm, jwt, limit, offset, func(ids []string, limit int64, skip int64) ([]model.Instance, error) {
+		return this.listInstances(ctx, limit, skip, sort, asc, filter, after, ids, ids == nil)
+	})
+}
+
+func instanceFilter(filter model.InstanceFilter, ids []string, ignoreIdFilter bool) bson.M {
+	result := bson.M{}
+	if filter.Search != "" {
+		result[nameKey] = primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search)}
+	}
+	if !ignoreIdFilter {
+		result[idKey] = bson.M{"$in": ids}
+	}
+	if filter.Owner != "" {
+		result[ownerKey] = filter.Owner
+	}
+	if filter.ImportTypeId != "" {
+		result[importTypeIdKey] = filter.ImportTypeId
+	}
+	if filter.Image != "" {
+		result[imageKey] = filter.Image
+	}
+	if filter.Restart != nil {
+		result[restartKey] = *filter.Restart
+	}
+	if filter.Generated != nil {
+		if *filter.Generated {
+			result[generatedKey] = true
+		} else {
+			// generatedKey == False || generatedKey == undefined to find legacy instances
+			result[generatedKey] = bson.M{"$ne": true}
+		}
+	}
+	if filter.CreatedAfter != nil || filter.CreatedBefore != nil {
+		created := bson.M{}
+		if filter.CreatedAfter != nil {
+			created["$gte"] = *filter.CreatedAfter
+		}
+		if filter.CreatedBefore != nil {
+			created["$lt"] = *filter.CreatedBefore
+		}
+		result[createdAtKey] = created
 	}
-	return this.listInstances(ctx, limit, offset, sort, asc, search, includeGenerated, ids, false)
+	if filter.UpdatedAfter != nil || filter.UpdatedBefore != nil {
+		updated := bson.M{}
+		if filter.UpdatedAfter != nil {
+			updated["$gte"] = *filter.UpdatedAfter
+		}
+		if filter.UpdatedBefore != nil {
+			updated["$lt"] = *filter.UpdatedBefore
+		}
+		result[updatedAtKey] = updated
+	}
+	for key, value := range filter.Labels {
+		result[labelsKey+"."+key] = value
+	}
+	return result
 }
 
-func (this *Mongo) listInstances(ctx context.Context, limit int64, offset int64, sort string, asc bool, search string, includeGenerated bool, ids []string, ignoreIdFilter bool) (result []model.Instance, err error) {
+func (this *Mongo) listInstances(ctx context.Context, limit int64, offset int64, sort string, asc bool, filter model.InstanceFilter, after *model.InstanceCursor, ids []string, ignoreIdFilter bool) (result []model.Instance, err error) {
 	opt := options.Find()
 	if limit != -1 {
 		opt.SetLimit(limit)
 	}
 	opt.SetSkip(offset)
 
-	sortby := idKey
-	switch sort {
-	case "id":
-		sortby = idKey
-	case "name":
-		sortby = nameKey
-	case "created_at":
-		sortby = createdAtKey
-	case "updated_at":
-		sortby = updatedAtKey
-	case "image":
-		sortby = imageKey
-	default:
-		sortby = idKey
-	}
+	sortby := instanceSortKey(sort)
 	direction := int32(1)
 	if !asc {
 		direction = int32(-1)
 	}
-	opt.SetSort(bson.D{{sortby, direction}})
-	var filter bson.M
-	if includeGenerated {
-		filter = bson.M{nameKey: primitive.Regex{
-			Pattern: ".*" + search + ".*",
-		}}
+	if sortby == idKey {
+		opt.SetSort(bson.D{{Key: idKey, Value: direction}})
 	} else {
-		// filter for generatedKey == False || generatedKey == undefined to find legacy instances
-		filter = bson.M{"$or": []bson.M{{generatedKey: false}, {generatedKey: bson.M{"$exists": false}}},
-			nameKey: primitive.Regex{
-				Pattern: ".*" + search + ".*",
-			}}
+		// the id makes the order unique, which is needed for cursors
+		opt.SetSort(bson.D{{Key: sortby, Value: direction}, {Key: idKey, Value: direction}})
 	}
-	if !ignoreIdFilter {
-		filter[idKey] = bson.M{"$in": ids}
+	query := instanceFilter(filter, ids, ignoreIdFilter)
+	if after != nil {
+		query = bson.M{"$and": []bson.M{query, cursorFilter(*after)}}
 	}
-	cursor, err := this.instanceCollection().Find(ctx, filter, opt)
+	cursor, err := this.instanceCollection().Find(ctx, query, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +304,47 @@ func (this *Mongo) listInstances(ctx context.Context, limit int64, offset int64,
 	return
 }
 
+func instanceSortKey(sort string) string {
+	switch model.NormalizeInstanceSort(sort) {
+	case "name":
+		return nameKey
+	case "created_at":
+		return createdAtKey
+	case "updated_at":
+		return updatedAtKey
+	case "image":
+		return imageKey
+	case "owner":
+		return ownerKey
+	case "import_type_id":
+		return importTypeIdKey
+	default:
+		return idKey
+	}
+}
+
+// cursorFilter matches the instances sorted behind the cursor
+func cursorFilter(after model.InstanceCursor) bson.M {
+	compare := "$gt"
+	if !after.Asc {
+		compare = "$lt"
+	}
+	sortby := instanceSortKey(after.Sort)
+	if sortby == idKey {
+		return bson.M{idKey: bson.M{compare: after.Id}}
+	}
+	var value interface{} = after.Value
+	if after.Time != nil {
+		value = *after.Time
+	}
+	return bson.M{"$or": []bson.M{
+		{sortby: bson.M{compare: value}},
+		{sortby: value, idKey: bson.M{compare: after.Id}},
+	}}
+}
+
 func (this *Mongo) CreateInstance(ctx context.Context, instance model.Instance, jwt jwt.Token) error {
+	instance.Configs = slices.Clone(instance.Configs) // keep the configs of the caller readable
 	for idx, conf := range instance.Configs {
 		err := configToWrite(&conf)
 		if err != nil {
@@ -222,7 +361,9 @@ func (this *Mongo) CreateInstance(ctx context.Context, instance model.Instance,
 		UserPermissions:  map[string]permV2Client.PermissionsMap{},
 		RolePermissions:  map[string]model2.PermissionsMap{},
 	}
+	_, span := tracing.Start(ctx, "permissions.GetResource")
 	permResource, err, code := this.perm.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id)
+	tracing.End(span, &err)
 	if err != nil && code != http.StatusNotFound {
 		return err
 	}
@@ -232,18 +373,23 @@ func (this *Mongo) CreateInstance(ctx context.Context, instance model.Instance,
 		permissions.RolePermissions = permResource.RolePermissions
 	}
 	model.SetDefaultPermissions(instance, permissions)
-	_, err, _ = this.perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id, permissions)
+	_, err, _ = this.SetInstancePermissions(ctx, instance.Id, permissions)
 	return err
 }
 
-func (this *Mongo) SetInstance(ctx context.Context, instance model.Instance, jwt jwt.Token) error {
+// SetInstance replaces the instance, if the stored version equals previousVersion. Otherwise model.ErrVersionConflict is returned.
+// Instances stored before versions were introduced have version 0.
+func (this *Mongo) SetInstance(ctx context.Context, instance model.Instance, jwt jwt.Token, previousVersion int64) error {
+	_, span := tracing.Start(ctx, "permissions.CheckPermission")
 	ok, err, _ := this.perm.CheckPermission(jwt.Token, model.PermV2InstanceTopic, instance.Id, permV2Client.Write)
+	tracing.End(span, &err)
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return errors.New("requested instance nonexistent or missing rights")
 	}
+	instance.Configs = slices.Clone(instance.Configs) // keep the configs of the caller readable
 	for idx, conf := range instance.Configs {
 		err := configToWrite(&conf)
 		if err != nil {
@@ -251,22 +397,109 @@ func (this *Mongo) SetInstance(ctx context.Context, instance model.Instance, jwt
 		}
 		instance.Configs[idx] = conf
 	}
-	_, err = this.instanceCollection().ReplaceOne(ctx, bson.M{idKey: instance.Id}, instance, options.Replace().SetUpsert(true))
+	filter := bson.M{idKey: instance.Id, versionKey: previousVersion}
+	if previousVersion == 0 {
+		filter[versionKey] = bson.M{"$in": bson.A{0, nil}} // nil matches missing fields
+	}
+	result, err := this.instanceCollection().ReplaceOne(ctx, filter, instance)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrVersionConflict
+	}
+	return nil
+}
+
+// SetInstanceServiceId updates only the service id of the instance. No permissions are checked and the version is kept.
+func (this *Mongo) SetInstanceServiceId(ctx context.Context, id string, serviceId string) error {
+	_, err := this.instanceCollection().UpdateOne(ctx, bson.M{idKey: id}, bson.M{"$set": bson.M{serviceIdKey: serviceId}})
+	return err
+}
+
+// SetInstanceVersion changes only the version of the instance, if the stored version equals previousVersion.
+// Otherwise model.ErrVersionConflict is returned. No permissions are checked.
+func (this *Mongo) SetInstanceVersion(ctx context.Context, id string, previousVersion int64, version int64) error {
+	filter := bson.M{idKey: id, versionKey: previousVersion}
+	if previousVersion == 0 {
+		filter[versionKey] = bson.M{"$in": bson.A{0, nil}} // nil matches missing fields
+	}
+	result, err := this.instanceCollection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{versionKey: version}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrVersionConflict
+	}
+	return nil
+}
+
+// TransferInstance changes the owner and moves the default permissions to the new owner, if the stored version equals previousVersion.
+// Otherwise model.ErrVersionConflict is returned. The version is incremented. No permissions are checked.
+func (this *Mongo) TransferInstance(ctx context.Context, instance model.Instance, newOwner string, previousVersion int64) (err error) {
+	result, err := this.instanceCollection().UpdateOne(ctx, bson.M{idKey: instance.Id, versionKey: previousVersion}, bson.M{
+		"$set": bson.M{
+			ownerKey:     newOwner,
+			updatedAtKey: time.Now(),
+			versionKey:   previousVersion + 1,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrVersionConflict
+	}
+	_, span := tracing.Start(ctx, "permissions.GetResource")
+	permResource, err, _ := this.perm.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, instance.Id)
+	tracing.End(span, &err)
 	if err != nil {
 		return err
 	}
+	permissions := permResource.ResourcePermissions
+	if permissions.UserPermissions == nil {
+		permissions.UserPermissions = map[string]permV2Client.PermissionsMap{}
+	}
+	model.RemoveDefaultPermissions(instance, permissions)
+	instance.Owner = newOwner
+	model.SetDefaultPermissions(instance, permissions)
+	_, err, _ = this.SetInstancePermissions(ctx, instance.Id, permissions)
+	return err
+}
+
+// SetInstancePermissions replaces the permissions of the instance. No permissions are checked.
+func (this *Mongo) SetInstancePermissions(ctx context.Context, id string, permissions permV2Client.ResourcePermissions) (result permV2Client.ResourcePermissions, err error, code int) {
+	_, span := tracing.Start(ctx, "permissions.SetPermission")
+	result, err, code = this.perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, id, permissions)
+	tracing.End(span, &err)
+	return result, err, code
+}
+
+// SetInstanceStale updates only the stale state of the instance. No permissions are checked.
+func (this *Mongo) SetInstanceStale(ctx context.Context, id string, stale bool, since *time.Time) error {
+	_, err := this.instanceCollection().UpdateOne(ctx, bson.M{idKey: id}, bson.M{"$set": bson.M{staleKey: stale, staleSinceKey: since}})
+	return err
+}
+
+// SetInstanceRuntime updates only the runtime status of the instance. No permissions are checked.
+func (this *Mongo) SetInstanceRuntime(ctx context.Context, id string, runtime model.RuntimeStatus) error {
+	_, err := this.instanceCollection().UpdateOne(ctx, bson.M{idKey: id}, bson.M{"$set": bson.M{runtimeKey: runtime}})
 	return err
 }
 
 func (this *Mongo) RemoveInstance(ctx context.Context, id string, jwt jwt.Token) error {
+	_, span := tracing.Start(ctx, "permissions.CheckPermission")
 	ok, err, _ := this.perm.CheckPermission(jwt.Token, model.PermV2InstanceTopic, id, permV2Client.Administrate)
+	tracing.End(span, &err)
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return errors.New("requested instance nonexistent or missing rights")
 	}
+	_, span = tracing.Start(ctx, "permissions.RemoveResource")
 	err, _ = this.perm.RemoveResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, id)
+	tracing.End(span, &err)
 	if err != nil {
 		return err
 	}
@@ -274,35 +507,51 @@ func (this *Mongo) RemoveInstance(ctx context.Context, id string, jwt jwt.Token)
 	return err
 }
 
-func (this *Mongo) CountInstances(ctx context.Context, jwt jwt.Token, search string, includeGenerated bool) (int64, error) {
-	ids, err, _ := this.perm.ListAccessibleResourceIds(jwt.Token, model.PermV2InstanceTopic, permV2Client.ListOptions{}, permV2Client.Read)
-	if err != nil {
-		return 0, err
-	}
-	filter := bson.D{{Key: idKey, Value: bson.M{"$in": ids}}}
-	if includeGenerated {
-		filter = append(filter, primitive.E{Key: nameKey, Value: primitive.Regex{
-			Pattern: ".*" + search + ".*",
-		}})
-	} else {
-		// filter for generatedKey == False || generatedKey == undefined to find legacy instances
-		filter = append(filter, primitive.E{
-			Key: "$or",
-			Value: []bson.M{
-				{generatedKey: false},
-				{generatedKey: bson.M{"$exists": false}},
-			},
-		})
+func (this *Mongo) CountInstances(ctx context.Context, jwt jwt.Token, filter model.InstanceFilter) (int64, error) {
+	return countReadable(ctx, this.perm, jwt, func(ids []string) (int64, error) {
+		return this.instanceCollection().CountDocuments(ctx, instanceFilter(filter, ids, false))
+	})
+}
+
+// AdminCountInstances counts the instances of all users
+func (this *Mongo) AdminCountInstances(ctx context.Context, filter model.InstanceFilter) (int64, error) {
+	return this.instanceCollection().CountDocuments(ctx, instanceFilter(filter, []string{}, true))
+}
 
-		filter = append(filter, primitive.E{
-			Key: nameKey,
-			Value: primitive.Regex{
-				Pattern: ".*" + search + ".*",
+// CountInstancesByImportTypeAndState counts all instances, independent of permissions
+func (this *Mongo) CountInstancesByImportTypeAndState(ctx context.Context) (result []model.InstanceCount, err error) {
+	cursor, err := this.instanceCollection().Aggregate(ctx, []bson.M{
+		{"$group": bson.M{
+			"_id": bson.M{
+				"import_type_id": "$" + importTypeIdKey,
+				"stale":          bson.M{"$ifNull": []interface{}{"$" + staleKey, false}},
 			},
+			"count": bson.M{"$sum": 1},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	groups := []struct {
+		Id struct {
+			ImportTypeId string `bson:"import_type_id"`
+			Stale        bool   `bson:"stale"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}{}
+	err = cursor.All(ctx, &groups)
+	if err != nil {
+		return nil, err
+	}
+	result = []model.InstanceCount{}
+	for _, group := range groups {
+		result = append(result, model.InstanceCount{
+			ImportTypeId: group.Id.ImportTypeId,
+			Stale:        group.Id.Stale,
+			Count:        group.Count,
 		})
 	}
-	count, err := this.instanceCollection().CountDocuments(ctx, filter)
-	return count, err
+	return result, nil
 }
 
 func configToWrite(config *model.InstanceConfig) error {
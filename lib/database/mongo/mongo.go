@@ -26,9 +26,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"log"
 	"reflect"
-	"sync"
 	"time"
 )
 
@@ -40,17 +41,12 @@ type Mongo struct {
 
 var CreateCollections = []func(db *Mongo) error{}
 
-func New(perm permV2Client.Client, conf config.Config, ctx context.Context, wg *sync.WaitGroup) (*Mongo, error) {
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(conf.MongoUrl))
+// New connects to mongo. The caller is responsible to call Disconnect after all users of the client are stopped.
+func New(perm permV2Client.Client, conf config.Config, ctx context.Context) (*Mongo, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(conf.MongoUrl).SetMonitor(otelmongo.NewMonitor()))
 	if err != nil {
 		return nil, err
 	}
-	wg.Add(1)
-	go func() {
-		<-ctx.Done()
-		_ = client.Disconnect(context.Background())
-		wg.Done()
-	}()
 	db := &Mongo{config: conf, client: client, perm: perm}
 	for _, creators := range CreateCollections {
 		err = creators(db)
@@ -107,7 +103,7 @@ func (this *Mongo) ensureIndex(collection *mongo.Collection, indexname string, i
 		direction = 1
 	}
 	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{indexKey, direction}},
+		Keys:    bson.D{{Key: indexKey, Value: direction}},
 		Options: options.Index().SetName(indexname).SetUnique(unique),
 	})
 	return err
@@ -149,3 +145,7 @@ func getBsonFieldName(obj interface{}, fieldName string) (bsonName string, err e
 func getTimeoutContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 10*time.Second)
 }
+
+func (this *Mongo) Ping(ctx context.Context) error {
+	return this.client.Ping(ctx, readpref.Primary())
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deliveries are removed after this duration
+const webhookDeliveryRetention = 30 * 24 * time.Hour
+
+var webhookIdKey string
+var webhookOwnerKey string
+var webhookActionsKey string
+var webhookInstanceIdsKey string
+var deliveryIdKey string
+var deliveryWebhookIdKey string
+var deliveryStatusKey string
+var deliveryNextAttemptAtKey string
+var deliveryCreatedAtKey string
+
+func init() {
+	var err error
+	webhookIdKey, err = getBsonFieldName(model.Webhook{}, "Id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhookOwnerKey, err = getBsonFieldName(model.Webhook{}, "Owner")
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhookActionsKey, err = getBsonFieldName(model.Webhook{}, "Actions")
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhookInstanceIdsKey, err = getBsonFieldName(model.Webhook{}, "InstanceIds")
+	if err != nil {
+		log.Fatal(err)
+	}
+	deliveryIdKey, err = getBsonFieldName(model.WebhookDelivery{}, "Id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	deliveryWebhookIdKey, err = getBsonFieldName(model.WebhookDelivery{}, "WebhookId")
+	if err != nil {
+		log.Fatal(err)
+	}
+	deliveryStatusKey, err = getBsonFieldName(model.WebhookDelivery{}, "Status")
+	if err != nil {
+		log.Fatal(err)
+	}
+	deliveryNextAttemptAtKey, err = getBsonFieldName(model.WebhookDelivery{}, "NextAttemptAt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	deliveryCreatedAtKey, err = getBsonFieldName(model.WebhookDelivery{}, "CreatedAt")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		collection := db.webhookCollection()
+		err = db.ensureIndex(collection, "webhookIdindex", webhookIdKey, true, true)
+		if err != nil {
+			return err
+		}
+		err = db.ensureIndex(collection, "webhookOwnerindex", webhookOwnerKey, true, false)
+		if err != nil {
+			return err
+		}
+		collection = db.webhookDeliveryCollection()
+		err = db.ensureIndex(collection, "deliveryIdindex", deliveryIdKey, true, true)
+		if err != nil {
+			return err
+		}
+		err = db.ensureCompoundIndex(collection, "deliveryWebhookIdCreatedAtindex", false, false, deliveryWebhookIdKey, deliveryCreatedAtKey)
+		if err != nil {
+			return err
+		}
+		err = db.ensureCompoundIndex(collection, "deliveryStatusNextAttemptAtindex", true, false, deliveryStatusKey, deliveryNextAttemptAtKey)
+		if err != nil {
+			return err
+		}
+		ctx, _ := getTimeoutContext()
+		_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: deliveryCreatedAtKey, Value: 1}},
+			Options: options.Index().SetName("deliveryRetentionindex").SetExpireAfterSeconds(int32(webhookDeliveryRetention.Seconds())),
+		})
+		return err
+	})
+}
+
+func (this *Mongo) webhookCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoWebhookCollection)
+}
+
+func (this *Mongo) webhookDeliveryCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoWebhookDeliveryCollection)
+}
+
+func (this *Mongo) ListWebhooks(ctx context.Context, owner string, limit int64, offset int64) (result []model.Webhook, err error) {
+	opt := options.Find().SetSort(bson.D{{Key: webhookIdKey, Value: 1}}).SetSkip(offset)
+	if limit != -1 {
+		opt.SetLimit(limit)
+	}
+	cursor, err := this.webhookCollection().Find(ctx, bson.M{webhookOwnerKey: owner}, opt)
+	if err != nil {
+		return nil, err
+	}
+	result = []model.Webhook{}
+	err = cursor.All(ctx, &result)
+	return result, err
+}
+
+// ListWebhooksForEvent returns all webhooks, independent of their owner, that subscribed to the action and instance
+func (this *Mongo) ListWebhooksForEvent(ctx context.Context, action model.InstanceEventAction, instanceId string) (result []model.Webhook, err error) {
+	filter := bson.M{"$and": []bson.M{
+		{"$or": []bson.M{{webhookActionsKey: bson.M{"$size": 0}}, {webhookActionsKey: action}}},
+		{"$or": []bson.M{{webhookInstanceIdsKey: bson.M{"$size": 0}}, {webhookInstanceIdsKey: instanceId}}},
+	}}
+	cursor, err := this.webhookCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result = []model.Webhook{}
+	err = cursor.All(ctx, &result)
+	return result, err
+}
+
+func (this *Mongo) GetWebhook(ctx context.Context, id string) (webhook model.Webhook, exists bool, err error) {
+	err = this.webhookCollection().FindOne(ctx, bson.M{webhookIdKey: id}).Decode(&webhook)
+	if err == mongo.ErrNoDocuments {
+		return webhook, false, nil
+	}
+	if err != nil {
+		return webhook, false, err
+	}
+	return webhook, true, nil
+}
+
+func (this *Mongo) SetWebhook(ctx context.Context, webhook model.Webhook) error {
+	if webhook.Actions == nil {
+		webhook.Actions = []model.InstanceEventAction{}
+	}
+	if webhook.InstanceIds == nil {
+		webhook.InstanceIds = []string{}
+	}
+	_, err := this.webhookCollection().ReplaceOne(ctx, bson.M{webhookIdKey: webhook.Id}, webhook, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *Mongo) RemoveWebhook(ctx context.Context, id string) error {
+	_, err := this.webhookCollection().DeleteOne(ctx, bson.M{webhookIdKey: id})
+	if err != nil {
+		return err
+	}
+	_, err = this.webhookDeliveryCollection().DeleteMany(ctx, bson.M{deliveryWebhookIdKey: id})
+	return err
+}
+
+func (this *Mongo) SetWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	_, err := this.webhookDeliveryCollection().ReplaceOne(ctx, bson.M{deliveryIdKey: delivery.Id}, delivery, options.Replace().SetUpsert(true))
+	return err
+}
+
+// ListWebhookDeliveries returns the deliveries of a webhook, newest first. Queued deliveries, whose recipient is not checked yet, are omitted.
+func (this *Mongo) ListWebhookDeliveries(ctx context.Context, webhookId string, limit int64, offset int64) (result []model.WebhookDelivery, err error) {
+	opt := options.Find().SetSort(bson.D{{Key: deliveryCreatedAtKey, Value: -1}}).SetSkip(offset)
+	if limit != -1 {
+		opt.SetLimit(limit)
+	}
+	cursor, err := this.webhookDeliveryCollection().Find(ctx, bson.M{deliveryWebhookIdKey: webhookId, deliveryStatusKey: bson.M{"$ne": model.WebhookDeliveryQueued}}, opt)
+	if err != nil {
+		return nil, err
+	}
+	result = []model.WebhookDelivery{}
+	err = cursor.All(ctx, &result)
+	return result, err
+}
+
+// ListDueWebhookDeliveries returns queued and pending deliveries with a next attempt before now, oldest first
+func (this *Mongo) ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) (result []model.WebhookDelivery, err error) {
+	opt := options.Find().SetSort(bson.D{{Key: deliveryNextAttemptAtKey, Value: 1}}).SetLimit(limit)
+	filter := bson.M{
+		deliveryStatusKey:        bson.M{"$in": []model.WebhookDeliveryStatus{model.WebhookDeliveryQueued, model.WebhookDeliveryPending}},
+		deliveryNextAttemptAtKey: bson.M{"$lte": now},
+	}
+	cursor, err := this.webhookDeliveryCollection().Find(ctx, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	result = []model.WebhookDelivery{}
+	err = cursor.All(ctx, &result)
+	return result, err
+}
+
+func (this *Mongo) RemoveWebhookDelivery(ctx context.Context, id string) error {
+	_, err := this.webhookDeliveryCollection().DeleteOne(ctx, bson.M{deliveryIdKey: id})
+	return err
+}
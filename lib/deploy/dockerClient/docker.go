@@ -17,14 +17,18 @@
 package dockerClient
 
 import (
+	"bytes"
 	"context"
-	"sync"
+	"strconv"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
 	"github.com/SENERGY-Platform/import-deploy/lib/util"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	docker "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 type DockerClient struct {
@@ -32,22 +36,16 @@ type DockerClient struct {
 	cli    *docker.Client
 }
 
-func New(config config.Config, ctx context.Context, wg *sync.WaitGroup) (client *DockerClient, err error) {
+func New(config config.Config) (client *DockerClient, err error) {
 	cli, err := docker.NewEnvClient()
 	if err != nil {
 		return nil, err
 	}
-	wg.Add(1)
-	go func() {
-		<-ctx.Done()
-		_ = cli.Close()
-		wg.Done()
-	}()
 	return &DockerClient{config: config, cli: cli}, nil
 }
 
-func (this *DockerClient) CreateContainer(name string, refStr string, env map[string]string, restart bool, _ string, _ string) (id string, err error) {
-	ctx, _ := util.GetTimeoutContext()
+func (this *DockerClient) CreateContainer(ctx context.Context, name string, refStr string, env map[string]string, restart bool, _ string, _ string) (id string, err error) {
+	ctx, _ = util.GetChildTimeoutContext(ctx)
 	if this.config.DockerPull == true {
 		_, err = this.cli.ImagePull(ctx, refStr, image.PullOptions{})
 		if err != nil {
@@ -82,28 +80,49 @@ func (this *DockerClient) CreateContainer(name string, refStr string, env map[st
 	return resp.ID, err
 }
 
-func (this *DockerClient) UpdateContainer(id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, _ bool) (newId string, err error) {
-	err = this.RemoveContainer(id)
+func (this *DockerClient) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, _ bool) (newId string, err error) {
+	err = this.RemoveContainer(ctx, id)
 	if err != nil {
 		return newId, err
 	}
-	return this.CreateContainer(name, image, env, restart, userid, importTypeId)
+	return this.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
 }
 
-func (this *DockerClient) RemoveContainer(id string) (err error) {
-	err = this.stopContainer(id)
+func (this *DockerClient) RemoveContainer(ctx context.Context, id string) (err error) {
+	err = this.stopContainer(ctx, id)
 	if err != nil {
 		return err
 	}
-	err = this.removeContainer(id)
+	err = this.removeContainer(ctx, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (this *DockerClient) ContainerExists(id string, _ *bool) (exists bool, err error) {
-	ctx, _ := util.GetTimeoutContext()
+// SetContainerOwner does nothing, because docker containers have no user label
+func (this *DockerClient) SetContainerOwner(_ context.Context, _ string, _ *bool, _ string) (err error) {
+	return nil
+}
+
+func (this *DockerClient) ContainerLogs(ctx context.Context, id string, _ *bool, tail int64) (logs string, err error) {
+	ctx, _ = util.GetChildTimeoutContext(ctx)
+	reader, err := this.cli.ContainerLogs(ctx, id, container.LogsOptions{ShowStdout: true, ShowStderr: true, Tail: strconv.FormatInt(tail, 10)})
+	if docker.IsErrNotFound(err) {
+		return logs, deploy.ErrNotFound
+	}
+	if err != nil {
+		return logs, err
+	}
+	defer reader.Close()
+	// containers are created without tty, stdout and stderr are multiplexed
+	buffer := &bytes.Buffer{}
+	_, err = stdcopy.StdCopy(buffer, buffer, reader)
+	return buffer.String(), err
+}
+
+func (this *DockerClient) ContainerExists(ctx context.Context, id string, _ *bool) (exists bool, err error) {
+	ctx, _ = util.GetChildTimeoutContext(ctx)
 	_, err = this.cli.ContainerInspect(ctx, id)
 	if err != nil {
 		if docker.IsErrNotFound(err) {
@@ -114,6 +133,41 @@ func (this *DockerClient) ContainerExists(id string, _ *bool) (exists bool, err
 	return true, nil
 }
 
+func (this *DockerClient) ContainerStatus(ctx context.Context, id string, _ *bool) (status model.RuntimeStatus, err error) {
+	ctx, _ = util.GetChildTimeoutContext(ctx)
+	info, err := this.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return status, err
+	}
+	status.Restarts = int64(info.RestartCount)
+	state := info.State
+	if state == nil {
+		status.State = model.RuntimePending
+		return status, nil
+	}
+	status.Message = state.Error
+	if state.OOMKilled {
+		status.Message = "OOMKilled"
+	}
+	switch {
+	case state.Restarting:
+		status.State = model.RuntimeCrashed
+	case state.Running:
+		status.State = model.RuntimeRunning
+	case state.Status == container.StateCreated:
+		status.State = model.RuntimePending
+	case state.ExitCode == 0:
+		status.State = model.RuntimeCompleted
+	default:
+		status.State = model.RuntimeCrashed
+	}
+	if !state.Running || state.Restarting {
+		exitCode := int32(state.ExitCode)
+		status.ExitCode = &exitCode
+	}
+	return status, nil
+}
+
 func (this *DockerClient) Disconnect() (err error) {
 	return this.cli.Close()
 }
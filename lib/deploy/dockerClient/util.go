@@ -60,14 +60,12 @@ func (this *DockerClient) removeAllContainers() (err error) {
 	return nil
 }
 
-func (this *DockerClient) stopContainer(id string) (err error) {
-	ctx := context.Background()
+func (this *DockerClient) stopContainer(ctx context.Context, id string) (err error) {
 	err = this.cli.ContainerStop(ctx, id, container.StopOptions{})
 	return err
 }
 
-func (this *DockerClient) removeContainer(id string) (err error) {
-	ctx := context.Background()
+func (this *DockerClient) removeContainer(ctx context.Context, id string) (err error) {
 	removeOptions := container.RemoveOptions{Force: true}
 
 	return this.cli.ContainerRemove(ctx, id, removeOptions)
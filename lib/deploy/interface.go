@@ -16,10 +16,26 @@
 
 package deploy
 
+import (
+	"context"
+	"errors"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+// ErrNotSupported is returned by backends for operations they can not provide
+var ErrNotSupported = errors.New("not supported by the deployment backend")
+
+// ErrNotFound is returned by ContainerLogs, if no container of the instance is running or was started yet
+var ErrNotFound = errors.New("container not found")
+
 type DeploymentClient interface {
-	CreateContainer(name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error)
-	UpdateContainer(id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error)
-	RemoveContainer(id string) (err error)
-	ContainerExists(id string, restart *bool) (exists bool, err error)
+	CreateContainer(ctx context.Context, name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error)
+	UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error)
+	RemoveContainer(ctx context.Context, id string) (err error)
+	ContainerExists(ctx context.Context, id string, restart *bool) (exists bool, err error)
+	ContainerStatus(ctx context.Context, id string, restart *bool) (status model.RuntimeStatus, err error)
+	SetContainerOwner(ctx context.Context, id string, restart *bool, userid string) (err error)
+	ContainerLogs(ctx context.Context, id string, restart *bool, tail int64) (logs string, err error)
 	Disconnect() (err error)
 }
@@ -1,14 +1,20 @@
 package kubernetes_api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
 	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
 	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -16,6 +22,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	autoscaling_k8s_io_v1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	autoscaler "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
 	"k8s.io/client-go/kubernetes"
@@ -48,6 +55,10 @@ func New(config config.Config) (client deploy.DeploymentClient, err error) {
 		}
 	}
 
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(rt)
+	})
+
 	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
@@ -60,8 +71,8 @@ func New(config config.Config) (client deploy.DeploymentClient, err error) {
 	return &k8s{clientset, autoscalerClientSet, config}, nil
 }
 
-func (this *k8s) CreateContainer(name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
-	ctx, cf := util.GetTimeoutContext()
+func (this *k8s) CreateContainer(ctx context.Context, name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
 	defer cf()
 	container := getContainer(name, image, env)
 	labels := map[string]string{
@@ -125,18 +136,18 @@ func (this *k8s) CreateContainer(name string, image string, env map[string]strin
 	return name, nil
 }
 
-func (this *k8s) UpdateContainer(id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error) {
+func (this *k8s) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error) {
 	if existingRestart != restart || !restart {
 		// cannot update restart policy, need to delete and recreate
 		// cannot update jobs, need to delete and recreate
-		err = this.RemoveContainer(id)
+		err = this.RemoveContainer(ctx, id)
 		if err != nil {
 			return newId, err
 		}
-		return this.CreateContainer(name, image, env, restart, userid, importTypeId)
+		return this.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
 	} else {
 		// update deployment
-		ctx, cf := util.GetTimeoutContext()
+		ctx, cf := util.GetChildTimeoutContext(ctx)
 		defer cf()
 		container := getContainer(name, image, env)
 		labels := map[string]string{
@@ -144,6 +155,18 @@ func (this *k8s) UpdateContainer(id string, name string, image string, env map[s
 			"importId":     name,
 			"importTypeId": strings.ReplaceAll(importTypeId, ":", "_"),
 		}
+		existing, err := this.clientset.AppsV1().Deployments(this.config.RancherNamespaceId).Get(ctx, id, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get deployment: %v", err)
+		}
+		if err != nil || existing.Spec.Selector == nil || !maps.Equal(existing.Spec.Selector.MatchLabels, labels) {
+			// selector is immutable (e.g. the user label changes on ownership transfer), need to delete and recreate
+			err = this.RemoveContainer(ctx, id)
+			if err != nil {
+				return newId, err
+			}
+			return this.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
+		}
 		deployment := getDeployment(name, labels, container)
 		_, err = this.clientset.AppsV1().Deployments(this.config.RancherNamespaceId).Update(ctx, deployment, metav1.UpdateOptions{})
 		if err != nil {
@@ -153,8 +176,8 @@ func (this *k8s) UpdateContainer(id string, name string, image string, env map[s
 	}
 }
 
-func (this *k8s) RemoveContainer(id string) (err error) {
-	ctx, cf := util.GetTimeoutContext()
+func (this *k8s) RemoveContainer(ctx context.Context, id string) (err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
 	defer cf()
 	var supErr error
 	mux := sync.Mutex{}
@@ -226,8 +249,8 @@ func (this *k8s) RemoveContainer(id string) (err error) {
 	return supErr
 }
 
-func (this *k8s) ContainerExists(id string, restart *bool) (exists bool, err error) {
-	ctx, cf := util.GetTimeoutContext()
+func (this *k8s) ContainerExists(ctx context.Context, id string, restart *bool) (exists bool, err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
 	defer cf()
 	found := false
 	if restart == nil || *restart { // default => restart enabled => deployment
@@ -248,6 +271,57 @@ func (this *k8s) ContainerExists(id string, restart *bool) (exists bool, err err
 	return found, nil
 }
 
+func (this *k8s) ContainerStatus(ctx context.Context, id string, _ *bool) (status model.RuntimeStatus, err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
+	defer cf()
+	pods, err := this.clientset.CoreV1().Pods(this.config.RancherNamespaceId).List(ctx, metav1.ListOptions{LabelSelector: "importId=" + id})
+	if err != nil {
+		return status, err
+	}
+	return deploy.PodRuntimeStatus(pods.Items), nil
+}
+
+// SetContainerOwner changes the user label of the deployment or job without restarting it.
+// The pods keep their user label, because it is part of the immutable selector.
+func (this *k8s) SetContainerOwner(ctx context.Context, id string, restart *bool, userid string) (err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
+	defer cf()
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]string{"user": userid}}})
+	if err != nil {
+		return err
+	}
+	if restart == nil || *restart {
+		_, err = this.clientset.AppsV1().Deployments(this.config.RancherNamespaceId).Patch(ctx, id, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to patch deployment: %v", err)
+		}
+	} else {
+		_, err = this.clientset.BatchV1().Jobs(this.config.RancherNamespaceId).Patch(ctx, id, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to patch job: %v", err)
+		}
+	}
+	return nil
+}
+
+func (this *k8s) ContainerLogs(ctx context.Context, id string, _ *bool, tail int64) (logs string, err error) {
+	ctx, cf := util.GetChildTimeoutContext(ctx)
+	defer cf()
+	pods, err := this.clientset.CoreV1().Pods(this.config.RancherNamespaceId).List(ctx, metav1.ListOptions{LabelSelector: "importId=" + id})
+	if err != nil {
+		return logs, err
+	}
+	pod, ok := deploy.NewestPod(pods.Items)
+	if !ok {
+		return logs, deploy.ErrNotFound
+	}
+	result, err := this.clientset.CoreV1().Pods(this.config.RancherNamespaceId).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tail}).DoRaw(ctx)
+	if err != nil {
+		return logs, fmt.Errorf("failed to read logs of pod %v: %v", pod.Name, err)
+	}
+	return string(result), nil
+}
+
 func (this *k8s) Disconnect() (err error) {
 	return nil
 }
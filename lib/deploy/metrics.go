@@ -0,0 +1,74 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package deploy
+
+import (
+	"context"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+// WithMetrics records latency and errors of all calls to client, labeled with backend
+func WithMetrics(client DeploymentClient, backend string, m *metrics.Metrics) DeploymentClient {
+	return &instrumentedClient{client: client, backend: backend, metrics: m}
+}
+
+type instrumentedClient struct {
+	client  DeploymentClient
+	backend string
+	metrics *metrics.Metrics
+}
+
+func (this *instrumentedClient) CreateContainer(ctx context.Context, name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "create", start, err) }(time.Now())
+	return this.client.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
+}
+
+func (this *instrumentedClient) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "update", start, err) }(time.Now())
+	return this.client.UpdateContainer(ctx, id, name, image, env, restart, userid, importTypeId, existingRestart)
+}
+
+func (this *instrumentedClient) RemoveContainer(ctx context.Context, id string) (err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "remove", start, err) }(time.Now())
+	return this.client.RemoveContainer(ctx, id)
+}
+
+func (this *instrumentedClient) ContainerExists(ctx context.Context, id string, restart *bool) (exists bool, err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "exists", start, err) }(time.Now())
+	return this.client.ContainerExists(ctx, id, restart)
+}
+
+func (this *instrumentedClient) ContainerStatus(ctx context.Context, id string, restart *bool) (status model.RuntimeStatus, err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "status", start, err) }(time.Now())
+	return this.client.ContainerStatus(ctx, id, restart)
+}
+
+func (this *instrumentedClient) SetContainerOwner(ctx context.Context, id string, restart *bool, userid string) (err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "owner", start, err) }(time.Now())
+	return this.client.SetContainerOwner(ctx, id, restart, userid)
+}
+
+func (this *instrumentedClient) ContainerLogs(ctx context.Context, id string, restart *bool, tail int64) (logs string, err error) {
+	defer func(start time.Time) { this.metrics.ObserveDeployCall(this.backend, "logs", start, err) }(time.Now())
+	return this.client.ContainerLogs(ctx, id, restart, tail)
+}
+
+func (this *instrumentedClient) Disconnect() (err error) {
+	return this.client.Disconnect()
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package deploy
+
+import (
+	"slices"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// waiting reasons of containers, which will not start without intervention or are restarted after an error
+var crashReasons = []string{"CrashLoopBackOff", "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError", "RunContainerError"}
+
+// NewestPod returns the latest created pod, which belongs to the current deployment of an instance
+func NewestPod(pods []corev1.Pod) (pod corev1.Pod, ok bool) {
+	if len(pods) == 0 {
+		return pod, false
+	}
+	pod = pods[0]
+	for _, p := range pods[1:] {
+		if p.CreationTimestamp.After(pod.CreationTimestamp.Time) {
+			pod = p
+		}
+	}
+	return pod, true
+}
+
+// PodRuntimeStatus evaluates the newest of the pods of an instance. Kubernetes based backends share this logic.
+func PodRuntimeStatus(pods []corev1.Pod) model.RuntimeStatus {
+	pod, ok := NewestPod(pods)
+	if !ok {
+		return model.RuntimeStatus{State: model.RuntimePending}
+	}
+	status := model.RuntimeStatus{Message: pod.Status.Message}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		status.State = model.RuntimeCompleted
+	case corev1.PodFailed:
+		status.State = model.RuntimeCrashed
+	case corev1.PodRunning:
+		status.State = model.RuntimeRunning
+	default:
+		status.State = model.RuntimePending
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		status.Restarts += int64(container.RestartCount)
+		terminated := container.State.Terminated
+		if terminated == nil {
+			terminated = container.LastTerminationState.Terminated
+		}
+		if terminated != nil {
+			exitCode := terminated.ExitCode
+			status.ExitCode = &exitCode
+			if terminated.Reason != "" {
+				status.Message = terminated.Reason
+			}
+		}
+		if waiting := container.State.Waiting; waiting != nil && slices.Contains(crashReasons, waiting.Reason) {
+			status.State = model.RuntimeCrashed
+			status.Message = waiting.Reason
+		}
+	}
+	return status
+}
@@ -17,10 +17,13 @@
 package rancher_api
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
 	"github.com/hashicorp/go-uuid"
 	"github.com/parnurzeal/gorequest"
 	"net/http"
@@ -38,7 +41,7 @@ func New(config config.Config) *Rancher {
 	return &Rancher{config.RancherUrl, config.RancherAccessKey, config.RancherSecretKey, config.RancherStackId}
 }
 
-func (r Rancher) CreateContainer(name string, image string, env map[string]string, restart bool, _ string, _ string) (id string, err error) {
+func (r Rancher) CreateContainer(_ context.Context, name string, image string, env map[string]string, restart bool, _ string, _ string) (id string, err error) {
 	id, err, _ = r.createContainer(name, image, env, restart)
 	return id, err
 }
@@ -90,7 +93,7 @@ func (r Rancher) createContainer(name string, image string, env map[string]strin
 	return
 }
 
-func (r Rancher) RemoveContainer(id string) (err error) {
+func (r Rancher) RemoveContainer(_ context.Context, id string) (err error) {
 	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
 	resp, body, e := request.Delete(r.url + "services/" + id).End()
 	if len(e) > 0 {
@@ -103,8 +106,8 @@ func (r Rancher) RemoveContainer(id string) (err error) {
 	return
 }
 
-func (r Rancher) UpdateContainer(id string, name string, image string, env map[string]string, restart bool, _ string, _ string, _ bool) (newId string, err error) {
-	err = r.RemoveContainer(id)
+func (r Rancher) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, _ string, _ string, _ bool) (newId string, err error) {
+	err = r.RemoveContainer(ctx, id)
 	if err != nil {
 		return newId, err
 	}
@@ -125,7 +128,7 @@ func (r Rancher) UpdateContainer(id string, name string, image string, env map[s
 	}
 }
 
-func (r Rancher) ContainerExists(id string, _ *bool) (exists bool, err error) {
+func (r Rancher) ContainerExists(_ context.Context, id string, _ *bool) (exists bool, err error) {
 	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
 	resp, _, errs := request.Get(r.url + "services/" + id).End()
 	if len(errs) > 0 {
@@ -137,6 +140,19 @@ func (r Rancher) ContainerExists(id string, _ *bool) (exists bool, err error) {
 	return resp.StatusCode == http.StatusOK, nil
 }
 
+func (r Rancher) ContainerStatus(_ context.Context, _ string, _ *bool) (status model.RuntimeStatus, err error) {
+	return status, deploy.ErrNotSupported
+}
+
+// SetContainerOwner does nothing, because rancher 1 containers have no user label
+func (r Rancher) SetContainerOwner(_ context.Context, _ string, _ *bool, _ string) (err error) {
+	return nil
+}
+
+func (r Rancher) ContainerLogs(_ context.Context, _ string, _ *bool, _ int64) (logs string, err error) {
+	return logs, deploy.ErrNotSupported
+}
+
 func (r Rancher) Disconnect() (err error) {
 	return nil // not needed
 }
@@ -17,14 +17,20 @@
 package rancher2_api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/deploy"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/parnurzeal/gorequest"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type Rancher2 struct {
@@ -34,23 +40,24 @@ type Rancher2 struct {
 	namespaceId string
 	projectId   string
 	kubeUrl     string
+	kubeApiUrl  string
 }
 
 func New(config config.Config) *Rancher2 {
-	kubeUrl := strings.TrimSuffix(config.RancherUrl, "v3/") + "k8s/clusters/" +
-		strings.Split(config.RancherProjectId, ":")[0] + "/v1/"
-	return &Rancher2{config.RancherUrl, config.RancherAccessKey, config.RancherSecretKey, config.RancherNamespaceId, config.RancherProjectId, kubeUrl}
+	clusterUrl := strings.TrimSuffix(config.RancherUrl, "v3/") + "k8s/clusters/" +
+		strings.Split(config.RancherProjectId, ":")[0]
+	return &Rancher2{config.RancherUrl, config.RancherAccessKey, config.RancherSecretKey, config.RancherNamespaceId, config.RancherProjectId, clusterUrl + "/v1/", clusterUrl + "/"}
 }
 
-func (r *Rancher2) UpdateContainer(id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, _ bool) (newId string, err error) {
-	err = r.RemoveContainer(id)
+func (r *Rancher2) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, _ bool) (newId string, err error) {
+	err = r.RemoveContainer(ctx, id)
 	if err != nil {
 		return newId, err
 	}
-	return r.CreateContainer(name, image, env, restart, userid, importTypeId)
+	return r.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
 }
 
-func (r *Rancher2) CreateContainer(name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
+func (r *Rancher2) CreateContainer(_ context.Context, name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
 	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
 	r2Env := []Env{}
 	for k, v := range env {
@@ -150,7 +157,7 @@ func (r *Rancher2) CreateContainer(name string, image string, env map[string]str
 	return name, err
 }
 
-func (r *Rancher2) RemoveContainer(id string) (err error) {
+func (r *Rancher2) RemoveContainer(_ context.Context, id string) (err error) {
 	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
 	resp, body, e := request.Delete(r.url + "projects/" + r.projectId + "/workloads/deployment:" +
 		r.namespaceId + ":" + id).End()
@@ -200,7 +207,7 @@ func (r *Rancher2) RemoveContainer(id string) (err error) {
 	return
 }
 
-func (r *Rancher2) ContainerExists(id string, _ *bool) (exists bool, err error) {
+func (r *Rancher2) ContainerExists(_ context.Context, id string, _ *bool) (exists bool, err error) {
 	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
 	resp, _, errs := request.Get(r.url + "projects/" + r.projectId + "/workloads/deployment:" +
 		r.namespaceId + ":" + id).End()
@@ -224,6 +231,71 @@ func (r *Rancher2) ContainerExists(id string, _ *bool) (exists bool, err error)
 	return true, nil
 }
 
+func (r *Rancher2) ContainerStatus(_ context.Context, id string, _ *bool) (status model.RuntimeStatus, err error) {
+	pods, err := r.listPods(id)
+	if err != nil {
+		return status, err
+	}
+	return deploy.PodRuntimeStatus(pods), nil
+}
+
+func (r *Rancher2) ContainerLogs(_ context.Context, id string, _ *bool, tail int64) (logs string, err error) {
+	pods, err := r.listPods(id)
+	if err != nil {
+		return logs, err
+	}
+	pod, ok := deploy.NewestPod(pods)
+	if !ok {
+		return logs, deploy.ErrNotFound
+	}
+	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
+	resp, body, errs := request.Get(r.kubeApiUrl + "api/v1/namespaces/" + r.namespaceId + "/pods/" + pod.Name + "/log?tailLines=" + strconv.FormatInt(tail, 10)).End()
+	if len(errs) > 0 {
+		return logs, errs[0]
+	}
+	if resp.StatusCode != http.StatusOK {
+		return logs, errors.New("unexpected status " + strconv.Itoa(resp.StatusCode) + ": " + body)
+	}
+	return body, nil
+}
+
+// listPods returns the pods of the import with the id
+func (r *Rancher2) listPods(id string) (pods []corev1.Pod, err error) {
+	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
+	resp, body, errs := request.Get(r.kubeUrl + "pods/" + r.namespaceId + "?labelSelector=" + url.QueryEscape("importId="+id)).End()
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status " + strconv.Itoa(resp.StatusCode) + ": " + body)
+	}
+	collection := PodCollection{}
+	err = json.Unmarshal([]byte(body), &collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection.Data, nil
+}
+
+// SetContainerOwner changes the user label of the workload with a merge patch of the kubernetes api, without restarting it.
+// The pods keep their user label, because it is part of the immutable selector.
+func (r *Rancher2) SetContainerOwner(_ context.Context, id string, restart *bool, userid string) (err error) {
+	patch := map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]string{"user": userid}}}
+	workload := "apis/apps/v1/namespaces/" + r.namespaceId + "/deployments/" + id
+	if restart != nil && !*restart {
+		workload = "apis/batch/v1/namespaces/" + r.namespaceId + "/jobs/" + id
+	}
+	request := gorequest.New().SetBasicAuth(r.accessKey, r.secretKey)
+	resp, body, errs := request.Patch(r.kubeApiUrl+workload).Set("Content-Type", "application/merge-patch+json").Send(patch).End()
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("could not update owner of import: " + body)
+	}
+	return nil
+}
+
 func (r *Rancher2) Disconnect() (err error) {
 	return nil // not needed
 }
@@ -16,6 +16,8 @@
 
 package rancher2_api
 
+import corev1 "k8s.io/api/core/v1"
+
 type Request struct {
 	Name        string            `json:"name,omitempty"`
 	NamespaceId string            `json:"namespaceId,omitempty"`
@@ -97,3 +99,8 @@ type MaxAllowed struct {
 	CPU    int64  `json:"cpu,omitempty"`
 	Memory string `json:"memory,omitempty"`
 }
+
+// PodCollection is returned by the kubernetes proxy of rancher when listing pods
+type PodCollection struct {
+	Data []corev1.Pod `json:"data"`
+}
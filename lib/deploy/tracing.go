@@ -0,0 +1,80 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package deploy
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithTracing wraps every call to client in a span, labeled with backend
+func WithTracing(client DeploymentClient, backend string) DeploymentClient {
+	return &tracedClient{client: client, backend: attribute.String("deploy.backend", backend)}
+}
+
+type tracedClient struct {
+	client  DeploymentClient
+	backend attribute.KeyValue
+}
+
+func (this *tracedClient) CreateContainer(ctx context.Context, name string, image string, env map[string]string, restart bool, userid string, importTypeId string) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "deploy.CreateContainer", this.backend, attribute.String("deploy.name", name), attribute.String("deploy.image", image))
+	defer tracing.End(span, &err)
+	return this.client.CreateContainer(ctx, name, image, env, restart, userid, importTypeId)
+}
+
+func (this *tracedClient) UpdateContainer(ctx context.Context, id string, name string, image string, env map[string]string, restart bool, userid string, importTypeId string, existingRestart bool) (newId string, err error) {
+	ctx, span := tracing.Start(ctx, "deploy.UpdateContainer", this.backend, attribute.String("deploy.id", id), attribute.String("deploy.image", image))
+	defer tracing.End(span, &err)
+	return this.client.UpdateContainer(ctx, id, name, image, env, restart, userid, importTypeId, existingRestart)
+}
+
+func (this *tracedClient) RemoveContainer(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "deploy.RemoveContainer", this.backend, attribute.String("deploy.id", id))
+	defer tracing.End(span, &err)
+	return this.client.RemoveContainer(ctx, id)
+}
+
+func (this *tracedClient) ContainerExists(ctx context.Context, id string, restart *bool) (exists bool, err error) {
+	ctx, span := tracing.Start(ctx, "deploy.ContainerExists", this.backend, attribute.String("deploy.id", id))
+	defer tracing.End(span, &err)
+	return this.client.ContainerExists(ctx, id, restart)
+}
+
+func (this *tracedClient) ContainerStatus(ctx context.Context, id string, restart *bool) (status model.RuntimeStatus, err error) {
+	ctx, span := tracing.Start(ctx, "deploy.ContainerStatus", this.backend, attribute.String("deploy.id", id))
+	defer tracing.End(span, &err)
+	return this.client.ContainerStatus(ctx, id, restart)
+}
+
+func (this *tracedClient) SetContainerOwner(ctx context.Context, id string, restart *bool, userid string) (err error) {
+	ctx, span := tracing.Start(ctx, "deploy.SetContainerOwner", this.backend, attribute.String("deploy.id", id))
+	defer tracing.End(span, &err)
+	return this.client.SetContainerOwner(ctx, id, restart, userid)
+}
+
+func (this *tracedClient) ContainerLogs(ctx context.Context, id string, restart *bool, tail int64) (logs string, err error) {
+	ctx, span := tracing.Start(ctx, "deploy.ContainerLogs", this.backend, attribute.String("deploy.id", id))
+	defer tracing.End(span, &err)
+	return this.client.ContainerLogs(ctx, id, restart, tail)
+}
+
+func (this *tracedClient) Disconnect() (err error) {
+	return this.client.Disconnect()
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+)
+
+const batchSize = 100
+const retryInterval = 10 * time.Second
+
+type Database interface {
+	AddInstanceEvent(ctx context.Context, event model.InstanceEvent) error
+	ListInstanceEvents(ctx context.Context, limit int64) (result []model.InstanceEvent, err error)
+	RemoveInstanceEvent(ctx context.Context, id string) error
+}
+
+// Outbox stores instance events in the database and relays them to kafka in the background.
+// Events stay in the database until kafka acknowledged them, so they survive kafka outages and restarts.
+type Outbox struct {
+	config  config.Config
+	db      Database
+	trigger chan struct{}
+}
+
+func New(conf config.Config, db Database, ctx context.Context, wg *sync.WaitGroup) *Outbox {
+	outbox := &Outbox{
+		config:  conf,
+		db:      db,
+		trigger: make(chan struct{}, 1),
+	}
+	if conf.InstanceEventsTopic == "" {
+		log.Println("instance events disabled")
+		return outbox
+	}
+	outbox.trigger <- struct{}{} // relay events left over from previous runs
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outbox.relay(ctx)
+	}()
+	return outbox
+}
+
+// Publish stores the event with ctx, which may carry the transaction of the mutation.
+// If the transaction is committed after the relay was triggered, the event is relayed after retryInterval.
+func (this *Outbox) Publish(ctx context.Context, event model.InstanceEvent) error {
+	if this.config.InstanceEventsTopic == "" {
+		return nil
+	}
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	err := this.db.AddInstanceEvent(timeoutCtx, event)
+	if err != nil {
+		return err
+	}
+	select {
+	case this.trigger <- struct{}{}:
+	default: // relay already triggered
+	}
+	return nil
+}
+
+func (this *Outbox) relay(ctx context.Context) {
+	var producer sarama.SyncProducer
+	defer func() {
+		if producer != nil {
+			_ = producer.Close()
+		}
+	}()
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-this.trigger:
+		}
+		if producer == nil {
+			var err error
+			producer, err = this.newProducer()
+			if err != nil {
+				log.Println("ERROR: unable to connect instance event producer", err)
+				continue
+			}
+		}
+		err := this.flush(producer)
+		if err != nil {
+			log.Println("ERROR: unable to relay instance events", err)
+		}
+	}
+}
+
+// flush sends all stored events in order and removes them from the outbox
+func (this *Outbox) flush(producer sarama.SyncProducer) error {
+	for {
+		ctx, _ := util.GetTimeoutContext()
+		events, err := this.db.ListInstanceEvents(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		for _, event := range events {
+			value, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+				Topic:     this.config.InstanceEventsTopic,
+				Key:       sarama.StringEncoder(event.InstanceId),
+				Value:     sarama.ByteEncoder(value),
+				Timestamp: event.Time,
+			})
+			if err != nil {
+				return err
+			}
+			ctx, _ := util.GetTimeoutContext()
+			err = this.db.RemoveInstanceEvent(ctx, event.Id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (this *Outbox) newProducer() (sarama.SyncProducer, error) {
+	sconfig := sarama.NewConfig()
+	sconfig.Version = sarama.V2_4_0_0
+	sconfig.Producer.Return.Successes = true
+	sconfig.Producer.RequiredAcks = sarama.WaitForAll
+	sconfig.Producer.Retry.Max = 3
+	return sarama.NewSyncProducer([]string{this.config.KafkaBootstrap}, sconfig)
+}
@@ -16,7 +16,17 @@
 
 package kafkaAdmin
 
+import (
+	"context"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
 type KafkaAdmin interface {
-	CreateTopic(name string) (err error)
-	DeleteTopic(name string) (err error)
+	CreateTopic(ctx context.Context, name string) (err error)
+	DeleteTopic(ctx context.Context, name string) (err error)
+	GetDataStats(ctx context.Context, topics []string) (result map[string]model.DataStats, err error)
+	ReadLatest(ctx context.Context, topic string, n int64, maxBytes int64, timeout time.Duration) (result []model.PreviewRecord, err error)
+	Ping(ctx context.Context) (err error)
 }
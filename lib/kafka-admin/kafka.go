@@ -17,8 +17,12 @@
 package kafkaAdmin
 
 import (
+	"context"
+
 	"github.com/IBM/sarama"
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type KafkaAdminImpl struct {
@@ -31,7 +35,9 @@ func New(config config.Config) (*KafkaAdminImpl, error) {
 	}, nil
 }
 
-func (this *KafkaAdminImpl) CreateTopic(name string) (err error) {
+func (this *KafkaAdminImpl) CreateTopic(ctx context.Context, name string) (err error) {
+	_, span := tracing.Start(ctx, "kafka.CreateTopic", attribute.String("messaging.destination.name", name))
+	defer tracing.End(span, &err)
 	admin, err := this.getAdmin()
 	if err != nil {
 		return err
@@ -54,7 +60,9 @@ func (this *KafkaAdminImpl) CreateTopic(name string) (err error) {
 	return admin.Close()
 }
 
-func (this *KafkaAdminImpl) DeleteTopic(name string) (err error) {
+func (this *KafkaAdminImpl) DeleteTopic(ctx context.Context, name string) (err error) {
+	_, span := tracing.Start(ctx, "kafka.DeleteTopic", attribute.String("messaging.destination.name", name))
+	defer tracing.End(span, &err)
 	admin, err := this.getAdmin()
 	if err != nil {
 		return err
@@ -66,6 +74,19 @@ func (this *KafkaAdminImpl) DeleteTopic(name string) (err error) {
 	return admin.Close()
 }
 
+// Ping connects to the cluster and fetches its metadata
+func (this *KafkaAdminImpl) Ping(ctx context.Context) (err error) {
+	_, span := tracing.Start(ctx, "kafka.Ping")
+	defer tracing.End(span, &err)
+	client, err := this.getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.Controller()
+	return err
+}
+
 func (this *KafkaAdminImpl) getAdmin() (admin sarama.ClusterAdmin, err error) {
 	sconfig := sarama.NewConfig()
 	sconfig.Version = sarama.V2_4_0_0
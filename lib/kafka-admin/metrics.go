@@ -0,0 +1,67 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package kafkaAdmin
+
+import (
+	"context"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+)
+
+// WithMetrics counts failed calls to admin
+func WithMetrics(admin KafkaAdmin, m *metrics.Metrics) KafkaAdmin {
+	return &instrumentedAdmin{admin: admin, metrics: m}
+}
+
+type instrumentedAdmin struct {
+	admin   KafkaAdmin
+	metrics *metrics.Metrics
+}
+
+func (this *instrumentedAdmin) CreateTopic(ctx context.Context, name string) (err error) {
+	err = this.admin.CreateTopic(ctx, name)
+	this.metrics.ObserveKafkaAdminCall("create_topic", err)
+	return err
+}
+
+func (this *instrumentedAdmin) DeleteTopic(ctx context.Context, name string) (err error) {
+	err = this.admin.DeleteTopic(ctx, name)
+	this.metrics.ObserveKafkaAdminCall("delete_topic", err)
+	return err
+}
+
+func (this *instrumentedAdmin) GetDataStats(ctx context.Context, topics []string) (result map[string]model.DataStats, err error) {
+	result, err = this.admin.GetDataStats(ctx, topics)
+	this.metrics.ObserveKafkaAdminCall("data_stats", err)
+	return result, err
+}
+
+// ReadLatest does not count ErrTopicNotFound, because it is an expected result
+func (this *instrumentedAdmin) ReadLatest(ctx context.Context, topic string, n int64, maxBytes int64, timeout time.Duration) (result []model.PreviewRecord, err error) {
+	result, err = this.admin.ReadLatest(ctx, topic, n, maxBytes, timeout)
+	if err != ErrTopicNotFound {
+		this.metrics.ObserveKafkaAdminCall("read_latest", err)
+	}
+	return result, err
+}
+
+func (this *instrumentedAdmin) Ping(ctx context.Context) (err error) {
+	err = this.admin.Ping(ctx)
+	this.metrics.ObserveKafkaAdminCall("ping", err)
+	return err
+}
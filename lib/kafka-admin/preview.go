@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafkaAdmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrTopicNotFound = errors.New("kafka topic not found")
+
+// ReadLatest returns up to n of the newest messages of the topic, newest first.
+// Messages larger than maxBytes are truncated. If the timeout is reached, the messages read so far are returned.
+func (this *KafkaAdminImpl) ReadLatest(ctx context.Context, topic string, n int64, maxBytes int64, timeout time.Duration) (result []model.PreviewRecord, err error) {
+	_, span := tracing.Start(ctx, "kafka.ReadLatest", attribute.String("messaging.destination.name", topic), attribute.Int64("n", n))
+	defer tracing.End(span, &err)
+	result = []model.PreviewRecord{}
+	if n <= 0 {
+		return result, nil
+	}
+	client, err := this.getClient()
+	if err != nil {
+		return result, err
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return result, err
+	}
+	defer consumer.Close()
+
+	partitions, err := client.Partitions(topic)
+	if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+		return result, ErrTopicNotFound
+	}
+	if err != nil {
+		return result, err
+	}
+
+	deadline := time.After(timeout)
+	for _, partition := range partitions {
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return result, err
+		}
+		oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return result, err
+		}
+		start := max(oldest, newest-n)
+		if start >= newest {
+			continue
+		}
+		partitionConsumer, err := consumer.ConsumePartition(topic, partition, start)
+		if err != nil {
+			return result, err
+		}
+		timeoutReached := false
+	read:
+		for {
+			select {
+			case msg := <-partitionConsumer.Messages():
+				result = append(result, toPreviewRecord(msg, maxBytes))
+				if msg.Offset >= newest-1 {
+					break read
+				}
+			case consumerErr := <-partitionConsumer.Errors():
+				_ = partitionConsumer.Close()
+				return result, consumerErr
+			case <-deadline:
+				timeoutReached = true
+				break read
+			}
+		}
+		_ = partitionConsumer.Close()
+		if timeoutReached {
+			break
+		}
+	}
+
+	slices.SortFunc(result, func(a, b model.PreviewRecord) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+	if int64(len(result)) > n {
+		result = result[:n]
+	}
+	return result, nil
+}
+
+func toPreviewRecord(msg *sarama.ConsumerMessage, maxBytes int64) (record model.PreviewRecord) {
+	record = model.PreviewRecord{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Timestamp: msg.Timestamp,
+		Key:       string(msg.Key),
+	}
+	value := msg.Value
+	if maxBytes > 0 && int64(len(value)) > maxBytes {
+		value = value[:maxBytes]
+		record.Truncated = true
+	}
+	if !record.Truncated && json.Valid(value) {
+		record.Value = value
+		return record
+	}
+	record.Value, _ = json.Marshal(string(value))
+	return record
+}
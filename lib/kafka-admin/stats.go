@@ -0,0 +1,165 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafkaAdmin
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	dataStatsTimeout = 10 * time.Second // for all topics of one call
+	dataStatsWorkers = 10               // partitions read in parallel
+)
+
+// GetDataStats returns offset based statistics for the given topics.
+// Topics unknown to the broker are omitted from the result. Partitions are read in parallel with one deadline for the call;
+// last messages, which are not read in time, are left out of last_message_at, offsets not read in time fail the call.
+func (this *KafkaAdminImpl) GetDataStats(ctx context.Context, topics []string) (result map[string]model.DataStats, err error) {
+	ctx, span := tracing.Start(ctx, "kafka.GetDataStats", attribute.Int("topics", len(topics)))
+	defer tracing.End(span, &err)
+	result = map[string]model.DataStats{}
+	if len(topics) == 0 {
+		return result, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, dataStatsTimeout)
+	defer cancel()
+	client, err := this.getClient()
+	if err != nil {
+		return result, err
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return result, err
+	}
+	defer consumer.Close()
+
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour).UnixMilli()
+	dayAgo := now.Add(-24 * time.Hour).UnixMilli()
+
+	partitions := map[string][]int32{}
+	for _, topic := range topics {
+		topicPartitions, err := client.Partitions(topic)
+		if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		partitions[topic] = topicPartitions
+		result[topic] = model.DataStats{}
+	}
+
+	mux := sync.Mutex{}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(dataStatsWorkers)
+	for topic, topicPartitions := range partitions {
+		for _, partition := range topicPartitions {
+			group.Go(func() error {
+				partitionStats, err := readPartitionStats(groupCtx, client, consumer, topic, partition, hourAgo, dayAgo)
+				if err != nil {
+					return err
+				}
+				mux.Lock()
+				defer mux.Unlock()
+				stats := result[topic]
+				stats.EndOffset += partitionStats.EndOffset
+				stats.MessagesLastHour += partitionStats.MessagesLastHour
+				stats.MessagesLastDay += partitionStats.MessagesLastDay
+				if partitionStats.LastMessageAt != nil && (stats.LastMessageAt == nil || partitionStats.LastMessageAt.After(*stats.LastMessageAt)) {
+					stats.LastMessageAt = partitionStats.LastMessageAt
+				}
+				result[topic] = stats
+				return nil
+			})
+		}
+	}
+	err = group.Wait()
+	if err != nil {
+		return map[string]model.DataStats{}, err
+	}
+	return result, nil
+}
+
+func readPartitionStats(ctx context.Context, client sarama.Client, consumer sarama.Consumer, topic string, partition int32, hourAgo int64, dayAgo int64) (stats model.DataStats, err error) {
+	if ctx.Err() != nil {
+		return stats, ctx.Err()
+	}
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return stats, err
+	}
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return stats, err
+	}
+	stats.EndOffset = newest
+	stats.MessagesLastHour = countSince(client, topic, partition, hourAgo, newest)
+	stats.MessagesLastDay = countSince(client, topic, partition, dayAgo, newest)
+	if newest > oldest {
+		timestamp, err := readTimestamp(ctx, consumer, topic, partition, newest-1)
+		if err != nil {
+			log.Println("WARNING: unable to read last message of", topic, partition, err)
+			return stats, nil
+		}
+		stats.LastMessageAt = &timestamp
+	}
+	return stats, nil
+}
+
+// countSince returns the number of messages in the partition with a timestamp at or after since (unix ms)
+func countSince(client sarama.Client, topic string, partition int32, since int64, newest int64) int64 {
+	offset, err := client.GetOffset(topic, partition, since)
+	if err != nil || offset < 0 {
+		// no message newer than since
+		return 0
+	}
+	return newest - offset
+}
+
+func readTimestamp(ctx context.Context, consumer sarama.Consumer, topic string, partition int32, offset int64) (timestamp time.Time, err error) {
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return timestamp, err
+	}
+	defer partitionConsumer.Close()
+	select {
+	case msg := <-partitionConsumer.Messages():
+		return msg.Timestamp, nil
+	case consumerErr := <-partitionConsumer.Errors():
+		return timestamp, consumerErr
+	case <-ctx.Done():
+		return timestamp, errors.New("timeout while reading message")
+	}
+}
+
+func (this *KafkaAdminImpl) getClient() (client sarama.Client, err error) {
+	sconfig := sarama.NewConfig()
+	sconfig.Version = sarama.V2_4_0_0
+	return sarama.NewClient([]string{this.config.KafkaBootstrap}, sconfig)
+}
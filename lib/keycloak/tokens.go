@@ -0,0 +1,136 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"golang.org/x/sync/singleflight"
+)
+
+var ErrNotConfigured = errors.New("keycloak is not configured")
+
+// ErrUnknownUser is returned if keycloak does not know the requested user
+var ErrUnknownUser = errors.New("unknown user")
+
+// expiryMargin is subtracted from the lifetime of cached tokens, so that they do not expire while a request is in flight
+const expiryMargin = 30 * time.Second
+
+type userToken struct {
+	token   jwt.Token
+	expires time.Time
+}
+
+// UserTokens exchanges tokens of users at keycloak and caches them until they expire.
+// The tokens carry the current roles and groups of the users.
+type UserTokens struct {
+	config    config.Config
+	tokens    map[string]userToken
+	mux       sync.Mutex
+	exchanges singleflight.Group
+}
+
+func NewUserTokens(config config.Config) *UserTokens {
+	return &UserTokens{config: config, tokens: map[string]userToken{}}
+}
+
+// Enabled is false, if no keycloak is configured
+func (this *UserTokens) Enabled() bool {
+	return this.config.KeycloakUrl != ""
+}
+
+// Get returns a cached token of the user or exchanges a new one. Concurrent calls for the same user share one exchange.
+func (this *UserTokens) Get(ctx context.Context, userId string) (token jwt.Token, err error) {
+	if !this.Enabled() {
+		return token, ErrNotConfigured
+	}
+	this.mux.Lock()
+	cached, ok := this.tokens[userId]
+	this.mux.Unlock()
+	if ok && time.Now().Add(expiryMargin).Before(cached.expires) {
+		return cached.token, nil
+	}
+	result, err, _ := this.exchanges.Do(userId, func() (any, error) {
+		_, span := tracing.Start(ctx, "keycloak.ExchangeUserToken")
+		token, expires, err := this.exchange(userId)
+		tracing.End(span, &err)
+		if err != nil {
+			return token, err
+		}
+		this.mux.Lock()
+		this.tokens[userId] = userToken{token: token, expires: expires}
+		this.mux.Unlock()
+		return token, nil
+	})
+	if err != nil {
+		return token, err
+	}
+	return result.(jwt.Token), nil
+}
+
+type exchangeError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// exchange requests a token of the user. Unlike jwt.ExchangeUserToken it keeps the error of keycloak,
+// so that unknown users can be told apart from other failures.
+func (this *UserTokens) exchange(userId string) (token jwt.Token, expires time.Time, err error) {
+	requested := time.Now()
+	resp, err := http.PostForm(this.config.KeycloakUrl+"/auth/realms/master/protocol/openid-connect/token", url.Values{
+		"client_id":         {this.config.KeycloakClientId},
+		"client_secret":     {this.config.KeycloakClientSecret},
+		"grant_type":        {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"requested_subject": {userId},
+	})
+	if err != nil {
+		return token, expires, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return token, expires, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		keycloakErr := exchangeError{}
+		_ = json.Unmarshal(body, &keycloakErr)
+		if resp.StatusCode == http.StatusBadRequest && keycloakErr.Description == "requested_subject not found" {
+			return token, expires, ErrUnknownUser
+		}
+		return token, expires, fmt.Errorf("token exchange failed with status %v: %v", resp.StatusCode, string(body))
+	}
+	result := jwt.OpenidToken{}
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return token, expires, err
+	}
+	token, err = jwt.Parse("Bearer " + result.AccessToken)
+	if err != nil {
+		return token, expires, err
+	}
+	return token, requested.Add(time.Duration(result.ExpiresIn * float64(time.Second))), nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package keycloak
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+)
+
+// fakeKeycloak answers token exchanges with unsigned tokens; exchanges for blocked users wait until release is closed.
+// The user "unknown" does not exist, exchanges for "broken" fail with 500 and tokens of "short" expire after 10s.
+func fakeKeycloak(t *testing.T, blocked string, release chan struct{}) (server *httptest.Server, exchanges map[string]int, mux *sync.Mutex) {
+	exchanges = map[string]int{}
+	mux = &sync.Mutex{}
+	server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		userId := request.FormValue("requested_subject")
+		mux.Lock()
+		exchanges[userId]++
+		mux.Unlock()
+		if userId == blocked {
+			<-release
+		}
+		switch userId {
+		case "unknown":
+			http.Error(writer, `{"error":"invalid_request","error_description":"requested_subject not found"}`, http.StatusBadRequest)
+			return
+		case "broken":
+			http.Error(writer, `{"error":"unknown_error"}`, http.StatusInternalServerError)
+			return
+		}
+		expiresIn := 300
+		if userId == "short" {
+			expiresIn = 10
+		}
+		encode := func(value any) string {
+			b, _ := json.Marshal(value)
+			return base64.RawURLEncoding.EncodeToString(b)
+		}
+		token := encode(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." + encode(map[string]any{"sub": userId}) + ".c2lnbmF0dXJl"
+		_ = json.NewEncoder(writer).Encode(map[string]any{"access_token": token, "expires_in": expiresIn})
+	}))
+	t.Cleanup(server.Close)
+	return server, exchanges, mux
+}
+
+func TestConcurrentGetSharesExchange(t *testing.T) {
+	release := make(chan struct{})
+	server, exchanges, mux := fakeKeycloak(t, "user1", release)
+	tokens := NewUserTokens(config.Config{KeycloakUrl: server.URL})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tokens.Get(context.Background(), "user1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if token.GetUserId() != "user1" {
+				t.Error(token.GetUserId())
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	_, err := tokens.Get(context.Background(), "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if exchanges["user1"] != 1 {
+		t.Error("expected one exchange", exchanges["user1"])
+	}
+}
+
+func TestGetDoesNotBlockOtherUsers(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server, _, _ := fakeKeycloak(t, "slow", release)
+	tokens := NewUserTokens(config.Config{KeycloakUrl: server.URL})
+
+	go func() {
+		_, _ = tokens.Get(context.Background(), "slow")
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error)
+	go func() {
+		_, err := tokens.Get(context.Background(), "fast")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("exchange of other user blocked")
+	}
+}
+
+func TestGetUnknownUser(t *testing.T) {
+	server, _, _ := fakeKeycloak(t, "", nil)
+	tokens := NewUserTokens(config.Config{KeycloakUrl: server.URL})
+	_, err := tokens.Get(context.Background(), "unknown")
+	if !errors.Is(err, ErrUnknownUser) {
+		t.Error(err)
+	}
+
+	_, err = tokens.Get(context.Background(), "broken")
+	if err == nil || errors.Is(err, ErrUnknownUser) {
+		t.Error("keycloak failure reported as unknown user", err)
+	}
+
+	server.Close()
+	_, err = tokens.Get(context.Background(), "user1")
+	if err == nil || errors.Is(err, ErrUnknownUser) {
+		t.Error("unreachable keycloak reported as unknown user", err)
+	}
+}
+
+func TestGetRenewsTokensBeforeExpiry(t *testing.T) {
+	server, exchanges, mux := fakeKeycloak(t, "", nil)
+	tokens := NewUserTokens(config.Config{KeycloakUrl: server.URL})
+	for _, userId := range []string{"user1", "user1", "short", "short"} {
+		_, err := tokens.Get(context.Background(), userId)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if exchanges["user1"] != 1 {
+		t.Error("valid token not reused", exchanges["user1"])
+	}
+	if exchanges["short"] != 2 {
+		t.Error("token within the expiry margin reused", exchanges["short"])
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/SENERGY-Platform/import-deploy/lib/api"
 	"github.com/SENERGY-Platform/import-deploy/lib/config"
@@ -31,14 +32,43 @@ import (
 	kubernetes_api "github.com/SENERGY-Platform/import-deploy/lib/deploy/kubernetes-api"
 	rancher_api "github.com/SENERGY-Platform/import-deploy/lib/deploy/rancher-api"
 	rancher2_api "github.com/SENERGY-Platform/import-deploy/lib/deploy/rancher2-api"
+	"github.com/SENERGY-Platform/import-deploy/lib/events"
 	kafkaAdmin "github.com/SENERGY-Platform/import-deploy/lib/kafka-admin"
+	"github.com/SENERGY-Platform/import-deploy/lib/keycloak"
+	"github.com/SENERGY-Platform/import-deploy/lib/metrics"
+	"github.com/SENERGY-Platform/import-deploy/lib/tracing"
+	"github.com/SENERGY-Platform/import-deploy/lib/webhooks"
 	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
 )
 
 func Start(conf config.Config, ctx context.Context) (wg *sync.WaitGroup, err error) {
 	wg = &sync.WaitGroup{}
-	perm := permV2Client.New(conf.PermissionV2Url)
-	data, err := database.New(conf, perm, ctx, wg)
+	shutdownTimeout, err := time.ParseDuration(conf.ShutdownTimeout)
+	if err != nil {
+		return wg, err
+	}
+
+	// background workers using the clients; stopped by ctx and awaited before the clients are disconnected
+	workers := &sync.WaitGroup{}
+	teardown := newShutdown(ctx, wg)
+
+	shutdownTracing, err := tracing.Init(conf, ctx)
+	if err != nil {
+		return wg, err
+	}
+	teardown.add(shutdownTracing) // registered first to run last
+
+	m := metrics.New()
+	perm := m.WithPermissionMetrics(permV2Client.New(conf.PermissionV2Url))
+	data, err := database.New(conf, perm, ctx)
+	if err != nil {
+		return wg, err
+	}
+	teardown.add(func() {
+		log.Println("disconnect database")
+		data.Disconnect()
+	})
+	err = m.RegisterInstanceCollector(data)
 	if err != nil {
 		return wg, err
 	}
@@ -47,7 +77,7 @@ func Start(conf config.Config, ctx context.Context) (wg *sync.WaitGroup, err err
 
 	switch conf.DeployMode {
 	case "docker":
-		deploymentClient, err = dockerClient.New(conf, ctx, wg)
+		deploymentClient, err = dockerClient.New(conf)
 	case "rancher1":
 		deploymentClient = rancher_api.New(conf)
 	case "rancher2":
@@ -60,27 +90,70 @@ func Start(conf config.Config, ctx context.Context) (wg *sync.WaitGroup, err err
 	if err != nil {
 		return wg, err
 	}
+	teardown.add(func() {
+		log.Println("disconnect deployment client")
+		err := deploymentClient.Disconnect()
+		if err != nil {
+			log.Println("WARNING: unable to disconnect deployment client", err)
+		}
+	})
+	deploymentClient = deploy.WithTracing(deploymentClient, conf.DeployMode)
+	deploymentClient = deploy.WithMetrics(deploymentClient, conf.DeployMode, m)
 
 	kafka, err := kafkaAdmin.New(conf)
 	if err != nil {
 		return wg, err
 	}
 
-	ctrl := controller.New(conf, data, deploymentClient, kafka, perm)
+	teardown.add(func() {
+		log.Println("wait for background workers")
+		workers.Wait()
+	})
+	outbox := events.New(conf, data, ctx, workers)
+	userTokens := keycloak.NewUserTokens(conf)
+	dispatcher, err := webhooks.New(conf, data, perm, userTokens, ctx, workers)
+	if err != nil {
+		return wg, err
+	}
+
+	ctrl := controller.New(conf, data, deploymentClient, kafkaAdmin.WithMetrics(kafka, m), perm, []controller.EventPublisher{outbox, dispatcher}, userTokens, m)
+	teardown.add(func() {
+		log.Println("wait for running operations")
+		ctrl.WaitForOperations()
+	})
 
 	if conf.StartupEnsureDeployed {
 		log.Println("Restoring missing import containers")
-		err = ctrl.EnsureAllInstancesDeployed()
+		err = ctrl.EnsureAllInstancesDeployed(ctx)
 		if err != nil {
 			return wg, err
 		}
 	}
 
-	err = api.Start(conf, ctrl)
+	err = ctrl.StartStaleCheck(ctx, workers)
+	if err != nil {
+		return wg, err
+	}
+
+	err = ctrl.StartRuntimeCheck(ctx, workers)
+	if err != nil {
+		return wg, err
+	}
+
+	server, err := api.Start(conf, ctrl, m)
 	if err != nil {
 		log.Println("ERROR: unable to start api", err)
 		return wg, err
 	}
+	teardown.add(func() {
+		log.Println("shutdown api")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Println("WARNING: unable to gracefully shutdown api", err)
+		}
+	})
 
 	return wg, err
 }
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// InstrumentHandle records requests of the handle labeled with the route pattern, to keep the label cardinality low
+func (this *Metrics) InstrumentHandle(method string, route string, handle httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+		handle(recorder, request, params)
+		status := strconv.Itoa(recorder.status)
+		this.httpRequests.WithLabelValues(method, route, status).Inc()
+		this.httpLatency.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (this *statusRecorder) WriteHeader(status int) {
+	if !this.wroteHeader {
+		this.status = status
+		this.wroteHeader = true
+	}
+	this.ResponseWriter.WriteHeader(status)
+}
+
+func (this *statusRecorder) Write(b []byte) (int, error) {
+	this.wroteHeader = true
+	return this.ResponseWriter.Write(b)
+}
+
+func (this *statusRecorder) Unwrap() http.ResponseWriter {
+	return this.ResponseWriter
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"log"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type InstanceCounter interface {
+	CountInstancesByImportTypeAndState(ctx context.Context) (result []model.InstanceCount, err error)
+}
+
+// instanceCollector counts the stored instances on each scrape
+type instanceCollector struct {
+	db           InstanceCounter
+	byState      *prometheus.Desc
+	byImportType *prometheus.Desc
+}
+
+func (this *Metrics) RegisterInstanceCollector(db InstanceCounter) error {
+	return this.registry.Register(&instanceCollector{
+		db: db,
+		byState: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "instances"),
+			"Number of instances per state.", []string{"state"}, nil),
+		byImportType: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "instances_by_import_type"),
+			"Number of instances per import type.", []string{"import_type_id"}, nil),
+	})
+}
+
+func (this *instanceCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- this.byState
+	descs <- this.byImportType
+}
+
+func (this *instanceCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, _ := util.GetTimeoutContext()
+	counts, err := this.db.CountInstancesByImportTypeAndState(ctx)
+	if err != nil {
+		log.Println("ERROR: unable to count instances for metrics", err)
+		return
+	}
+	byState := map[string]int64{"active": 0, "stale": 0}
+	byImportType := map[string]int64{}
+	for _, count := range counts {
+		if count.Stale {
+			byState["stale"] += count.Count
+		} else {
+			byState["active"] += count.Count
+		}
+		byImportType[count.ImportTypeId] += count.Count
+	}
+	for state, count := range byState {
+		metrics <- prometheus.MustNewConstMetric(this.byState, prometheus.GaugeValue, float64(count), state)
+	}
+	for importTypeId, count := range byImportType {
+		metrics <- prometheus.MustNewConstMetric(this.byImportType, prometheus.GaugeValue, float64(count), importTypeId)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "import_deploy"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests      *prometheus.CounterVec
+	httpLatency       *prometheus.HistogramVec
+	operations        *prometheus.CounterVec
+	operationLatency  *prometheus.HistogramVec
+	deployCalls       *prometheus.CounterVec
+	deployLatency     *prometheus.HistogramVec
+	kafkaAdminErrors  *prometheus.CounterVec
+	permissionCalls   *prometheus.CounterVec
+	permissionLatency *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of handled http requests.",
+		}, []string{"method", "route", "status"}),
+		httpLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of handled http requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "controller_operations_total",
+			Help:      "Number of controller operations.",
+		}, []string{"operation", "result"}),
+		operationLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "controller_operation_duration_seconds",
+			Help:      "Duration of controller operations.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"operation"}),
+		deployCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "deploy_calls_total",
+			Help:      "Number of calls to the deployment backend.",
+		}, []string{"backend", "call", "result"}),
+		deployLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "deploy_call_duration_seconds",
+			Help:      "Duration of calls to the deployment backend.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"backend", "call"}),
+		kafkaAdminErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "kafka_admin_errors_total",
+			Help:      "Number of failed kafka admin operations.",
+		}, []string{"operation"}),
+		permissionCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "permission_calls_total",
+			Help:      "Number of calls to the permissions service.",
+		}, []string{"call", "result"}),
+		permissionLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "permission_call_duration_seconds",
+			Help:      "Duration of calls to the permissions service.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"call"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpLatency,
+		m.operations,
+		m.operationLatency,
+		m.deployCalls,
+		m.deployLatency,
+		m.kafkaAdminErrors,
+		m.permissionCalls,
+		m.permissionLatency,
+	)
+	return m
+}
+
+func (this *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(this.registry, promhttp.HandlerOpts{Registry: this.registry})
+}
+
+// ObserveOperation records a controller operation. err is a pointer to allow usage in defer statements with named results.
+func (this *Metrics) ObserveOperation(operation string, start time.Time, err *error) {
+	this.operations.WithLabelValues(operation, result(*err)).Inc()
+	this.operationLatency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func (this *Metrics) ObserveDeployCall(backend string, call string, start time.Time, err error) {
+	this.deployCalls.WithLabelValues(backend, call, result(err)).Inc()
+	this.deployLatency.WithLabelValues(backend, call).Observe(time.Since(start).Seconds())
+}
+
+func (this *Metrics) ObserveKafkaAdminCall(operation string, err error) {
+	if err != nil {
+		this.kafkaAdminErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (this *Metrics) ObservePermissionCall(call string, start time.Time, err error) {
+	this.permissionCalls.WithLabelValues(call, result(err)).Inc()
+	this.permissionLatency.WithLabelValues(call).Observe(time.Since(start).Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
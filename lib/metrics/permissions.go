@@ -0,0 +1,70 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package metrics
+
+import (
+	"time"
+
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+)
+
+// WithPermissionMetrics records latency and errors of the permission calls used by this service.
+// All other calls are passed through unobserved.
+func (this *Metrics) WithPermissionMetrics(client permV2Client.Client) permV2Client.Client {
+	return &permissionClient{Client: client, metrics: this}
+}
+
+type permissionClient struct {
+	permV2Client.Client
+	metrics *Metrics
+}
+
+func (this *permissionClient) CheckPermission(token string, topicId string, id string, permissions ...permV2Client.Permission) (access bool, err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("check_permission", start, err) }(time.Now())
+	return this.Client.CheckPermission(token, topicId, id, permissions...)
+}
+
+func (this *permissionClient) CheckMultiplePermissions(token string, topicId string, ids []string, permissions ...permV2Client.Permission) (access map[string]bool, err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("check_multiple_permissions", start, err) }(time.Now())
+	return this.Client.CheckMultiplePermissions(token, topicId, ids, permissions...)
+}
+
+func (this *permissionClient) ListAccessibleResourceIds(token string, topicId string, options permV2Client.ListOptions, permissions ...permV2Client.Permission) (ids []string, err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("list_accessible_resource_ids", start, err) }(time.Now())
+	return this.Client.ListAccessibleResourceIds(token, topicId, options, permissions...)
+}
+
+func (this *permissionClient) ListResourcesWithAdminPermission(token string, topicId string, options permV2Client.ListOptions) (result []permV2Client.Resource, err error, code int) {
+	defer func(start time.Time) {
+		this.metrics.ObservePermissionCall("list_resources_with_admin_permission", start, err)
+	}(time.Now())
+	return this.Client.ListResourcesWithAdminPermission(token, topicId, options)
+}
+
+func (this *permissionClient) GetResource(token string, topicId string, id string) (result permV2Client.Resource, err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("get_resource", start, err) }(time.Now())
+	return this.Client.GetResource(token, topicId, id)
+}
+
+func (this *permissionClient) SetPermission(token string, topicId string, id string, permissions permV2Client.ResourcePermissions) (result permV2Client.ResourcePermissions, err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("set_permission", start, err) }(time.Now())
+	return this.Client.SetPermission(token, topicId, id, permissions)
+}
+
+func (this *permissionClient) RemoveResource(token string, topicId string, id string) (err error, code int) {
+	defer func(start time.Time) { this.metrics.ObservePermissionCall("remove_resource", start, err) }(time.Now())
+	return this.Client.RemoveResource(token, topicId, id)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// ApplyKeyLabel stores the key of the manifest entry, which manages the instance
+const ApplyKeyLabel = "apply-key"
+
+// ApplyManifest describes the desired instances of a user
+type ApplyManifest struct {
+	Instances []ManifestInstance `json:"instances"`
+}
+
+// ManifestInstance is identified by Key, which is stable across renames
+type ManifestInstance struct {
+	Key              string            `json:"key"`
+	Name             string            `json:"name"`
+	ImportTypeId     string            `json:"import_type_id"`
+	Configs          []InstanceConfig  `json:"configs"` // missing configs get the default of the import type
+	Restart          *bool             `json:"restart,omitempty"`
+	ExpectedInterval string            `json:"expected_interval,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+}
+
+type ApplyOptions struct {
+	DryRun bool
+	Prune  bool // delete instances of the user, which are not part of the manifest; generated instances are kept
+}
+
+type ApplyAction string
+
+const (
+	ApplyActionCreate    ApplyAction = "create"
+	ApplyActionUpdate    ApplyAction = "update"
+	ApplyActionDelete    ApplyAction = "delete"
+	ApplyActionUnchanged ApplyAction = "unchanged"
+)
+
+// ApplyResult contains the plan. It is only executed if no step has an error and DryRun is false.
+type ApplyResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Applied bool        `json:"applied"`
+	Steps   []ApplyStep `json:"steps"`
+}
+
+type ApplyStep struct {
+	Key           string      `json:"key,omitempty"` // empty for pruned instances without key
+	Action        ApplyAction `json:"action"`
+	InstanceId    string      `json:"instance_id,omitempty"`
+	Name          string      `json:"name"`
+	ChangedFields []string    `json:"changed_fields,omitempty"`
+	Error         string      `json:"error,omitempty"` // planning or execution error
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+const InstanceBundleVersion = 1
+
+// InstanceBundle is exported from one installation and imported into another
+type InstanceBundle struct {
+	Version    int                  `json:"version"`
+	ExportedAt time.Time            `json:"exported_at"`
+	Instances  []InstanceDefinition `json:"instances"`
+}
+
+// InstanceDefinition contains the user defined fields of an instance
+type InstanceDefinition struct {
+	Name             string            `json:"name"`
+	ImportTypeId     string            `json:"import_type_id"`
+	Configs          []InstanceConfig  `json:"configs"`
+	Restart          *bool             `json:"restart,omitempty"`
+	ExpectedInterval string            `json:"expected_interval,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	OmittedConfigs   []string          `json:"omitted_configs,omitempty"` // names of secret configs, which were not exported
+}
+
+// OnConflict selects how imported definitions are handled, if the user already has an instance with the same name
+type OnConflict string
+
+const (
+	OnConflictFail   OnConflict = "fail"   // import nothing
+	OnConflictSkip   OnConflict = "skip"   // keep the existing instance
+	OnConflictUpdate OnConflict = "update" // update the existing instance
+	OnConflictCreate OnConflict = "create" // create another instance with the same name
+)
+
+type ImportOptions struct {
+	DryRun            bool
+	OnConflict        OnConflict
+	ImportTypeMapping map[string]string // import type id in the bundle -> import type id of this installation
+}
+
+type ImportAction string
+
+const (
+	ImportActionCreate ImportAction = "create"
+	ImportActionUpdate ImportAction = "update"
+	ImportActionSkip   ImportAction = "skip"
+	ImportActionError  ImportAction = "error"
+)
+
+// ImportReport lists the planned (dry_run) or executed action of each definition in the order of the bundle
+type ImportReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Results []ImportResult `json:"results"`
+}
+
+type ImportResult struct {
+	Name         string       `json:"name"`
+	ImportTypeId string       `json:"import_type_id"` // after mapping
+	Action       ImportAction `json:"action"`
+	InstanceId   string       `json:"instance_id,omitempty"` // existing or created instance
+	Error        string       `json:"error,omitempty"`
+	Warnings     []string     `json:"warnings,omitempty"`
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+)
+
+// InstanceSortKeys are the supported sort keys of instance lists; other keys sort by id
+var InstanceSortKeys = []string{"id", "name", "created_at", "updated_at", "image", "owner", "import_type_id"}
+
+// NormalizeInstanceSort returns sort if it is supported and "id" otherwise
+func NormalizeInstanceSort(sort string) string {
+	if slices.Contains(InstanceSortKeys, sort) {
+		return sort
+	}
+	return "id"
+}
+
+// InstanceCursor points behind the last instance of a page. The id breaks ties between equal sort values.
+// Clients receive it as opaque string, encrypted with the key of NewCursorKey, because the sort value may be the owner.
+type InstanceCursor struct {
+	Sort  string     `json:"s"`
+	Asc   bool       `json:"a"`
+	Id    string     `json:"i"`
+	Value string     `json:"v,omitempty"` // used by string sort keys
+	Time  *time.Time `json:"t,omitempty"` // used by time sort keys
+}
+
+// NewInstanceCursor returns the cursor of the page following last
+func NewInstanceCursor(sort string, asc bool, last Instance) InstanceCursor {
+	result := InstanceCursor{Sort: NormalizeInstanceSort(sort), Asc: asc, Id: last.Id}
+	switch result.Sort {
+	case "name":
+		result.Value = last.Name
+	case "image":
+		result.Value = last.Image
+	case "owner":
+		result.Value = last.Owner
+	case "import_type_id":
+		result.Value = last.ImportTypeId
+	case "created_at":
+		result.Time = &last.CreatedAt
+	case "updated_at":
+		result.Time = &last.UpdatedAt
+	}
+	return result
+}
+
+// NewCursorKey derives the cursor key from secret
+func NewCursorKey(secret string) []byte {
+	key := sha256.Sum256([]byte(secret))
+	return key[:]
+}
+
+// Encrypt returns the cursor as opaque string; key is a 32 byte key of NewCursorKey
+func (this InstanceCursor) Encrypt(key []byte) (string, error) {
+	aead, err := cursorCipher(key)
+	if err != nil {
+		return "", err
+	}
+	plain, err := json.Marshal(this)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// ParseInstanceCursor decrypts cursor and checks that it was created for the same sort order
+func ParseInstanceCursor(cursor string, key []byte, sort string, asc bool) (result InstanceCursor, err error) {
+	aead, err := cursorCipher(key)
+	if err != nil {
+		return result, err
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) < aead.NonceSize() {
+		return result, errors.New("invalid cursor")
+	}
+	plain, err := aead.Open(nil, b[:aead.NonceSize()], b[aead.NonceSize():], nil)
+	if err != nil {
+		return result, errors.New("invalid cursor")
+	}
+	err = json.Unmarshal(plain, &result)
+	if err != nil || result.Id == "" {
+		return result, errors.New("invalid cursor")
+	}
+	if result.Sort != NormalizeInstanceSort(sort) || result.Asc != asc {
+		return result, errors.New("cursor was created for a different sort order")
+	}
+	return result, nil
+}
+
+func cursorCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,26 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+type DataStats struct {
+	EndOffset        int64      `json:"end_offset"`
+	MessagesLastHour int64      `json:"messages_last_hour"`
+	MessagesLastDay  int64      `json:"messages_last_day"`
+	LastMessageAt    *time.Time `json:"last_message_at"` // nil if the topic holds no messages
+}
@@ -0,0 +1,27 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import "errors"
+
+// ErrVersionConflict is returned if an instance was changed after the expected version
+var ErrVersionConflict = errors.New("the instance was modified, read it again and retry")
+
+// ErrorResponse is the body of error responses of the /v2 api
+type ErrorResponse struct {
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// InstanceEventVersion is increased on incompatible changes of InstanceEvent
+const InstanceEventVersion = 1
+
+type InstanceEventAction string
+
+const (
+	InstanceCreated     InstanceEventAction = "create"
+	InstanceUpdated     InstanceEventAction = "update"
+	InstanceDeleted     InstanceEventAction = "delete"
+	InstanceTransferred InstanceEventAction = "transfer" // owner changed
+
+	InstanceStale     InstanceEventAction = "stale"     // no data within the expected interval
+	InstanceRecovered InstanceEventAction = "recovered" // data after being stale
+
+	InstanceCrashed   InstanceEventAction = "crash"     // container exited with an error or was restarted
+	InstanceCompleted InstanceEventAction = "completed" // container exited successfully
+)
+
+type InstanceEvent struct {
+	Id            string              `json:"id"`
+	Version       int                 `json:"version"`
+	Action        InstanceEventAction `json:"action"`
+	InstanceId    string              `json:"instance_id"`
+	Owner         string              `json:"owner"`
+	ImportTypeId  string              `json:"import_type_id"`
+	KafkaTopic    string              `json:"kafka_topic"`
+	Time          time.Time           `json:"time"`
+	ChangedFields []string            `json:"changed_fields,omitempty"` // json names of changed instance fields, only set for updates
+	PerformedBy   string              `json:"performed_by,omitempty"`   // id of the admin, if the action was performed on behalf of the owner
+	PreviousOwner string              `json:"previous_owner,omitempty"` // only set for transfers
+	Runtime       *RuntimeStatus      `json:"runtime,omitempty"`        // only set for crash and completed
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+import "time"
+
+// InstanceFilter restricts listed and counted instances. Zero values do not filter.
+type InstanceFilter struct {
+	Search        string // substring of the name
+	Owner         string // user id
+	ImportTypeId  string
+	Image         string
+	Restart       *bool
+	Generated     *bool             // false includes instances created before the generated flag existed
+	CreatedAfter  *time.Time        // inclusive
+	CreatedBefore *time.Time        // exclusive
+	UpdatedAfter  *time.Time        // inclusive
+	UpdatedBefore *time.Time        // exclusive
+	Labels        map[string]string // all labels have to match
+}
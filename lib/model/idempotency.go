@@ -0,0 +1,29 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// IdempotencyKey records a create request with an Idempotency-Key header and its response
+type IdempotencyKey struct {
+	Id          string    `json:"id"` // user id and key
+	RequestHash string    `json:"request_hash"`
+	InstanceId  string    `json:"instance_id"` // empty while the request is in progress
+	Response    string    `json:"response"`    // json of the created instance
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
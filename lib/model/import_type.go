@@ -17,13 +17,14 @@
 package model
 
 type ImportType struct {
-	Id             string             `json:"id"`
-	Name           string             `json:"name"`
-	Description    string             `json:"description"`
-	Image          string             `json:"image"`
-	DefaultRestart bool               `json:"default_restart"`
-	Configs        []ImportTypeConfig `json:"configs"`
-	Owner          string             `json:"owner"`
+	Id                      string             `json:"id"`
+	Name                    string             `json:"name"`
+	Description             string             `json:"description"`
+	Image                   string             `json:"image"`
+	DefaultRestart          bool               `json:"default_restart"`
+	Configs                 []ImportTypeConfig `json:"configs"`
+	Owner                   string             `json:"owner"`
+	DefaultExpectedInterval string             `json:"default_expected_interval"`
 }
 
 type ImportTypeConfig struct {
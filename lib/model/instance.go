@@ -16,24 +16,35 @@
 
 package model
 
-import "time"
-import permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+import (
+	"regexp"
+	"time"
+
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+)
 
 type Instances []Instance
 
 type Instance struct {
-	Id           string           `json:"id"`
-	Name         string           `json:"name"`
-	ImportTypeId string           `json:"import_type_id"`
-	Image        string           `json:"image"`
-	KafkaTopic   string           `json:"kafka_topic"`
-	Configs      []InstanceConfig `json:"configs"`
-	Restart      *bool            `json:"restart"`
-	ServiceId    string           `json:"-"`
-	Owner        string           `json:"-"`
-	CreatedAt    time.Time        `json:"created_at"`
-	UpdatedAt    time.Time        `json:"updated_at"`
-	Generated    bool             `json:"generated"`
+	Id               string            `json:"id"`
+	Name             string            `json:"name"`
+	ImportTypeId     string            `json:"import_type_id"`
+	Image            string            `json:"image"`
+	KafkaTopic       string            `json:"kafka_topic"`
+	Configs          []InstanceConfig  `json:"configs"`
+	Restart          *bool             `json:"restart"`
+	ServiceId        string            `json:"-"`
+	Owner            string            `json:"-"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	Generated        bool              `json:"generated"`
+	ExpectedInterval string            `json:"expected_interval,omitempty"` // max duration without new messages before the instance is marked as stale, e.g. "1h"
+	Stale            bool              `json:"stale"`
+	StaleSince       *time.Time        `json:"stale_since,omitempty"`
+	DataStats        *DataStats        `json:"data_stats,omitempty" bson:"-"`
+	Labels           map[string]string `json:"labels,omitempty"`  // user defined, keys may contain letters, digits, '_' and '-'
+	Version          int64             `json:"version"`           // incremented by every update and transfer, returned as ETag
+	Runtime          *RuntimeStatus    `json:"runtime,omitempty"` // last observed state of the container, managed by the service
 }
 
 type InstanceConfig struct {
@@ -52,3 +63,49 @@ func SetDefaultPermissions(instance Instance, permissions permV2Client.ResourceP
 		Administrate: true,
 	}
 }
+
+// RemoveDefaultPermissions reverts SetDefaultPermissions, e.g. before the owner changes
+func RemoveDefaultPermissions(instance Instance, permissions permV2Client.ResourcePermissions) {
+	delete(permissions.UserPermissions, instance.Owner)
+}
+
+type InstanceCount struct {
+	ImportTypeId string `json:"import_type_id"`
+	Stale        bool   `json:"stale"`
+	Count        int64  `json:"count"`
+}
+
+// AdminInstance exposes the owner, which is hidden from users
+type AdminInstance struct {
+	Instance
+	Owner string `json:"owner"`
+}
+
+type AdminInstanceList struct {
+	Total      int64           `json:"total"` // number of instances matching the filter, independent of limit and offset
+	Instances  []AdminInstance `json:"instances"`
+	NextCursor string          `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+// InstanceList is a page of instances of the /v2 api
+type InstanceList struct {
+	Items      []Instance `json:"items"`
+	Total      int64      `json:"total"`                 // number of instances matching the filter, independent of limit, offset and cursor
+	NextCursor string     `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+type TransferRequest struct {
+	NewOwner string `json:"new_owner"` // user id
+}
+
+type TransferResult struct {
+	Transferred []string          `json:"transferred"`      // instance ids
+	Errors      map[string]string `json:"errors,omitempty"` // instance id -> error message
+}
+
+var labelKeyPattern = regexp.MustCompile("^[a-zA-Z0-9_-]{1,63}$")
+
+// ValidLabelKey checks the key of a label; keys are used in mongo field paths and must not contain '.' or '$'
+func ValidLabelKey(key string) bool {
+	return labelKeyPattern.MatchString(key)
+}
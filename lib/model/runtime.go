@@ -0,0 +1,33 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package model
+
+type RuntimeState string
+
+const (
+	RuntimePending   RuntimeState = "pending" // container not started yet
+	RuntimeRunning   RuntimeState = "running"
+	RuntimeCrashed   RuntimeState = "crashed"   // exited with an error or waiting to be restarted after an error
+	RuntimeCompleted RuntimeState = "completed" // exited successfully, only expected for instances without restart
+)
+
+// RuntimeStatus is the state of the container of an instance, as reported by the deployment backend
+type RuntimeStatus struct {
+	State    RuntimeState `json:"state"`
+	Restarts int64        `json:"restarts"`
+	ExitCode *int32       `json:"exit_code,omitempty"` // of the last terminated container
+	Message  string       `json:"message,omitempty"`
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Message struct {
+	UserId  string `json:"userId"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Topic   string `json:"topic"`
+}
+
+const Topic = "import"
+
+// duplicate messages of the same user within this window are dropped by the notifier
+const ignoreDuplicatesWithinSeconds = 3600
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func Send(endpoint string, message Message) error {
+	if endpoint == "" {
+		return nil
+	}
+	b, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(endpoint+"/notifications?ignore_duplicates_within_seconds="+strconv.Itoa(ignoreDuplicatesWithinSeconds), "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		respMsg, _ := io.ReadAll(resp.Body)
+		return errors.New("unexpected notifier response " + strconv.Itoa(resp.StatusCode) + ": " + string(respMsg))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package lib
+
+import (
+	"context"
+	"sync"
+)
+
+// shutdown runs the registered steps in reverse order of registration, after ctx is done.
+// Steps registered after the shutdown started are executed immediately.
+type shutdown struct {
+	mux     sync.Mutex
+	steps   []func()
+	started bool
+}
+
+func newShutdown(ctx context.Context, wg *sync.WaitGroup) *shutdown {
+	result := &shutdown{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		result.run()
+	}()
+	return result
+}
+
+func (this *shutdown) add(step func()) {
+	this.mux.Lock()
+	if this.started {
+		this.mux.Unlock()
+		step()
+		return
+	}
+	defer this.mux.Unlock()
+	this.steps = append(this.steps, step)
+}
+
+func (this *shutdown) run() {
+	this.mux.Lock()
+	this.started = true
+	steps := this.steps
+	this.mux.Unlock()
+	for i := len(steps) - 1; i >= 0; i-- {
+		steps[i]()
+	}
+}
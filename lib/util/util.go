@@ -24,3 +24,8 @@ import (
 func GetTimeoutContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 10*time.Second)
 }
+
+// GetChildTimeoutContext returns a context with the default timeout, which keeps the values (e.g. the trace) of parent
+func GetChildTimeoutContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, 10*time.Second)
+}
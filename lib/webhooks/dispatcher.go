@@ -0,0 +1,310 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/keycloak"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	"github.com/SENERGY-Platform/import-deploy/lib/util"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+	"github.com/hashicorp/go-uuid"
+)
+
+const batchSize = 100
+const pollInterval = 5 * time.Second
+const minBackoff = 10 * time.Second
+const maxBackoff = time.Hour
+
+const SignatureHeader = "X-Import-Deploy-Signature"
+const EventHeader = "X-Import-Deploy-Event"
+const DeliveryHeader = "X-Import-Deploy-Delivery"
+
+type Database interface {
+	ListWebhooksForEvent(ctx context.Context, action model.InstanceEventAction, instanceId string) (result []model.Webhook, err error)
+	GetWebhook(ctx context.Context, id string) (webhook model.Webhook, exists bool, err error)
+	SetWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int64) (result []model.WebhookDelivery, err error)
+	RemoveWebhookDelivery(ctx context.Context, id string) error
+}
+
+// Dispatcher queues a delivery for each webhook matching a published event. In the background, it checks whether the
+// webhook owner may read the instance and sends the deliveries.
+// Failed deliveries are retried with exponential backoff until config.WebhookMaxAttempts is reached.
+type Dispatcher struct {
+	config     config.Config
+	db         Database
+	perm       permV2Client.Client
+	userTokens *keycloak.UserTokens
+	client     *http.Client
+	trigger    chan struct{}
+}
+
+func New(conf config.Config, db Database, perm permV2Client.Client, userTokens *keycloak.UserTokens, ctx context.Context, wg *sync.WaitGroup) (*Dispatcher, error) {
+	timeout, err := time.ParseDuration(conf.WebhookTimeout)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := newTargetPolicy(conf.WebhookAllowedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // the dialed address is checked, which would be the proxy
+	transport.DialContext = (&net.Dialer{Timeout: timeout, Control: policy.control}).DialContext
+	dispatcher := &Dispatcher{
+		config:     conf,
+		db:         db,
+		perm:       perm,
+		userTokens: userTokens,
+		client:     &http.Client{Timeout: timeout, Transport: transport},
+		trigger:    make(chan struct{}, 1),
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dispatcher.run(ctx)
+	}()
+	return dispatcher, nil
+}
+
+// Publish queues the deliveries with ctx, which may carry the transaction of the mutation.
+// Permissions and keycloak are only called by the background dispatcher, so that they do not delay the transaction.
+func (this *Dispatcher) Publish(ctx context.Context, event model.InstanceEvent) error {
+	timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+	webhooks, err := this.db.ListWebhooksForEvent(timeoutCtx, event.Action, event.InstanceId)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, webhook := range webhooks {
+		id, err := uuid.GenerateUUID()
+		if err != nil {
+			return err
+		}
+		timeoutCtx, _ := util.GetChildTimeoutContext(ctx)
+		err = this.db.SetWebhookDelivery(timeoutCtx, model.WebhookDelivery{
+			Id:            id,
+			WebhookId:     webhook.Id,
+			Event:         event,
+			Status:        model.WebhookDeliveryQueued,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	select {
+	case this.trigger <- struct{}{}:
+	default: // already triggered
+	}
+	return nil
+}
+
+// mayRead checks the read permission of the webhook owner with the current roles and groups of the owner.
+// Permissions of deleted instances are removed, in this case only the instance owner and admins are notified.
+// Roles and groups are resolved by exchanging a token of the owner at keycloak. Without keycloak, or if the exchange fails,
+// only permissions granted to the owner directly are considered.
+func (this *Dispatcher) mayRead(ctx context.Context, webhook model.Webhook, event model.InstanceEvent, resource permV2Client.Resource, resourceExists bool) bool {
+	if webhook.Owner == event.Owner || (resourceExists && resource.UserPermissions[webhook.Owner].Read) {
+		return true
+	}
+	if !this.userTokens.Enabled() {
+		return false
+	}
+	token, err := this.userTokens.Get(ctx, webhook.Owner)
+	if err != nil {
+		log.Println("WARNING: unable to resolve roles and groups of webhook owner", webhook.Owner, err)
+		return false
+	}
+	if token.IsAdmin() {
+		return true
+	}
+	if !resourceExists {
+		return false
+	}
+	for _, group := range token.GetGroups() {
+		if resource.GroupPermissions[group].Read {
+			return true
+		}
+	}
+	for _, role := range token.GetRoles() {
+		if resource.RolePermissions[role].Read {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-this.trigger:
+		}
+		err := this.deliverDue(ctx)
+		if err != nil {
+			log.Println("ERROR: unable to deliver webhooks", err)
+		}
+	}
+}
+
+func (this *Dispatcher) deliverDue(ctx context.Context) error {
+	for {
+		listCtx, _ := util.GetTimeoutContext()
+		deliveries, err := this.db.ListDueWebhookDeliveries(listCtx, time.Now(), batchSize)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if delivery.Status == model.WebhookDeliveryQueued {
+				allowed, err := this.checkRecipient(ctx, delivery)
+				if err != nil {
+					return err
+				}
+				if !allowed {
+					removeCtx, _ := util.GetTimeoutContext()
+					err = this.db.RemoveWebhookDelivery(removeCtx, delivery.Id)
+					if err != nil {
+						return err
+					}
+					continue
+				}
+				delivery.Status = model.WebhookDeliveryPending
+			}
+			delivery = this.attempt(delivery)
+			setCtx, _ := util.GetTimeoutContext()
+			err = this.db.SetWebhookDelivery(setCtx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkRecipient returns whether the owner of the webhook may receive the event of a queued delivery.
+// Deliveries of removed webhooks are dropped.
+func (this *Dispatcher) checkRecipient(ctx context.Context, delivery model.WebhookDelivery) (bool, error) {
+	getCtx, _ := util.GetTimeoutContext()
+	webhook, exists, err := this.db.GetWebhook(getCtx, delivery.WebhookId)
+	if err != nil || !exists {
+		return false, err
+	}
+	resource, err, code := this.perm.GetResource(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, delivery.Event.InstanceId)
+	if err != nil && code != http.StatusNotFound {
+		return false, err
+	}
+	return this.mayRead(ctx, webhook, delivery.Event, resource, code != http.StatusNotFound), nil
+}
+
+func (this *Dispatcher) attempt(delivery model.WebhookDelivery) model.WebhookDelivery {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+	code, err := this.send(delivery)
+	delivery.LastStatusCode = code
+	if err == nil {
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		return delivery
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= this.config.WebhookMaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts))
+	return delivery
+}
+
+func backoff(attempts int64) time.Duration {
+	wait := minBackoff
+	for i := int64(1); i < attempts && wait < maxBackoff; i++ {
+		wait = wait * 2
+	}
+	return min(wait, maxBackoff)
+}
+
+func (this *Dispatcher) send(delivery model.WebhookDelivery) (code int, err error) {
+	ctx, _ := util.GetTimeoutContext()
+	webhook, exists, err := this.db.GetWebhook(ctx, delivery.WebhookId)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, errors.New("webhook removed")
+	}
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewBuffer(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.Event.Action))
+	req.Header.Set(DeliveryHeader, delivery.Id)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.New("unexpected status code " + strconv.Itoa(resp.StatusCode))
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the value of the signature header: "sha256=" followed by the hex encoded HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
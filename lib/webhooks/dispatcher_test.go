@@ -0,0 +1,152 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/import-deploy/lib/config"
+	"github.com/SENERGY-Platform/import-deploy/lib/keycloak"
+	"github.com/SENERGY-Platform/import-deploy/lib/model"
+	permV2Client "github.com/SENERGY-Platform/permissions-v2/pkg/client"
+)
+
+// fakeDatabase keeps webhooks and deliveries in memory
+type fakeDatabase struct {
+	mux        sync.Mutex
+	webhooks   map[string]model.Webhook
+	deliveries map[string]model.WebhookDelivery
+}
+
+func (this *fakeDatabase) ListWebhooksForEvent(_ context.Context, _ model.InstanceEventAction, _ string) (result []model.Webhook, err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	for _, webhook := range this.webhooks {
+		result = append(result, webhook)
+	}
+	return result, nil
+}
+
+func (this *fakeDatabase) GetWebhook(_ context.Context, id string) (model.Webhook, bool, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	webhook, ok := this.webhooks[id]
+	return webhook, ok, nil
+}
+
+func (this *fakeDatabase) SetWebhookDelivery(_ context.Context, delivery model.WebhookDelivery) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.deliveries[delivery.Id] = delivery
+	return nil
+}
+
+func (this *fakeDatabase) ListDueWebhookDeliveries(_ context.Context, now time.Time, _ int64) (result []model.WebhookDelivery, err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	for _, delivery := range this.deliveries {
+		due := delivery.Status == model.WebhookDeliveryQueued || delivery.Status == model.WebhookDeliveryPending
+		if due && !delivery.NextAttemptAt.After(now) {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+func (this *fakeDatabase) RemoveWebhookDelivery(_ context.Context, id string) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	delete(this.deliveries, id)
+	return nil
+}
+
+func (this *fakeDatabase) byWebhook() map[string]model.WebhookDelivery {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	result := map[string]model.WebhookDelivery{}
+	for _, delivery := range this.deliveries {
+		result[delivery.WebhookId] = delivery
+	}
+	return result
+}
+
+func TestPublishQueuesAndDispatcherChecksRecipients(t *testing.T) {
+	calls := 0
+	mux := sync.Mutex{}
+	target := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mux.Lock()
+		calls++
+		mux.Unlock()
+	}))
+	t.Cleanup(target.Close)
+	db := &fakeDatabase{
+		webhooks: map[string]model.Webhook{
+			"owner": {Id: "owner", Owner: "user1", Url: target.URL},
+			"other": {Id: "other", Owner: "user2", Url: target.URL},
+		},
+		deliveries: map[string]model.WebhookDelivery{},
+	}
+	// without permissions client and keycloak, publishing would panic if it checked recipients
+	dispatcher := &Dispatcher{config: config.Config{WebhookMaxAttempts: 3}, db: db, client: http.DefaultClient, trigger: make(chan struct{}, 1)}
+	err := dispatcher.Publish(context.Background(), model.InstanceEvent{Id: "event1", InstanceId: "instance1", Owner: "user1", Action: model.InstanceUpdated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, delivery := range db.byWebhook() {
+		if delivery.Status != model.WebhookDeliveryQueued {
+			t.Error(id, delivery.Status)
+		}
+	}
+
+	perm, err := permV2Client.NewTestClient(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err, _ = perm.SetTopic(permV2Client.InternalAdminToken, permV2Client.Topic{Id: model.PermV2InstanceTopic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err, _ = perm.SetPermission(permV2Client.InternalAdminToken, model.PermV2InstanceTopic, "instance1", permV2Client.ResourcePermissions{
+		UserPermissions: map[string]permV2Client.PermissionsMap{"user1": {Read: true, Write: true, Execute: true, Administrate: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.perm = perm
+	dispatcher.userTokens = keycloak.NewUserTokens(config.Config{})
+	err = dispatcher.deliverDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliveries := db.byWebhook()
+	if deliveries["owner"].Status != model.WebhookDeliveryDelivered {
+		t.Error("delivery to the instance owner", deliveries["owner"])
+	}
+	if _, ok := deliveries["other"]; ok {
+		t.Error("delivery to a user without read permission kept", deliveries["other"])
+	}
+	mux.Lock()
+	defer mux.Unlock()
+	if calls != 1 {
+		t.Error(calls)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+var ErrForbiddenTarget = errors.New("webhook target resolves to a private, loopback, link-local or otherwise internal address")
+
+// internal networks not covered by the methods of netip.Addr
+var internalNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// targetPolicy rejects internal addresses, unless they are part of an allowed network
+type targetPolicy struct {
+	allowed []netip.Prefix
+}
+
+// newTargetPolicy parses the comma separated CIDRs of allowedNetworks
+func newTargetPolicy(allowedNetworks string) (policy targetPolicy, err error) {
+	for _, network := range strings.Split(allowedNetworks, ",") {
+		network = strings.TrimSpace(network)
+		if network == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return policy, fmt.Errorf("invalid webhook_allowed_networks entry %v: %w", network, err)
+		}
+		policy.allowed = append(policy.allowed, prefix)
+	}
+	return policy, nil
+}
+
+func (this targetPolicy) permits(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range this.allowed {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range internalNetworks {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// control is used as net.Dialer.Control, to check the address after name resolution and on every redirect
+func (this targetPolicy) control(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !this.permits(ip) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
+
+// CheckTarget resolves host and returns ErrForbiddenTarget if any of its addresses is internal and not part of allowedNetworks.
+// Deliveries check the address again when connecting, because the name may resolve differently later.
+func CheckTarget(ctx context.Context, allowedNetworks string, host string) error {
+	policy, err := newTargetPolicy(allowedNetworks)
+	if err != nil {
+		return err
+	}
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("unable to resolve webhook host: %w", err)
+	}
+	for _, ip := range ips {
+		if !policy.permits(ip) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestTargetPolicy(t *testing.T) {
+	policy, err := newTargetPolicy("10.1.0.0/16, fd00::/64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for address, expected := range map[string]bool{
+		"93.184.215.14":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.0.0.1":         false,
+		"10.1.2.3":         true,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          true,
+		"fd01::1":          false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		if actual := policy.permits(netip.MustParseAddr(address)); actual != expected {
+			t.Error(address, actual, expected)
+		}
+	}
+	_, err = newTargetPolicy("10.1.0.0")
+	if err == nil {
+		t.Error("expected error for invalid network")
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	err := CheckTarget(context.Background(), "", "localhost")
+	if !errors.Is(err, ErrForbiddenTarget) {
+		t.Error(err)
+	}
+	err = CheckTarget(context.Background(), "127.0.0.0/8,::1/128", "localhost")
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -38,15 +38,8 @@ func main() {
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	wg, err := lib.Start(conf, ctx)
-	if err != nil {
-		cancel()
-		if wg != nil {
-			wg.Wait()
-		}
-		log.Fatal(err)
-	}
 
+	// listen for signals before starting, to allow graceful shutdown during startup reconciliation
 	var shutdownTime time.Time
 	go func() {
 		shutdown := make(chan os.Signal, 1)
@@ -57,6 +50,15 @@ func main() {
 		cancel()
 	}()
 
+	wg, err := lib.Start(conf, ctx)
+	if err != nil {
+		cancel()
+		if wg != nil {
+			wg.Wait()
+		}
+		log.Fatal(err)
+	}
+
 	wg.Wait()
 	log.Println("Shutdown complete, took", time.Since(shutdownTime))
 }